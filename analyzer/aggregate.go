@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// isErrAggregatorCall reports whether the call combines errors into one,
+// which includes appending to a slice of errors.
+func isErrAggregatorCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if ident, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+		if builtin, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); ok {
+			return builtin.Name() == "append" && typeIsErrorSlice(pass.TypesInfo.TypeOf(call))
+		}
+	}
+
+	_, model, ok := getErrFuncModel(pass, call)
+
+	return ok && model.aggregates
+}
+
+// inspectErrAccumulation reports accumulating only other errors in place
+// of the checked one, like "errs = append(errs, other)" or
+// "merr = multierr.Append(merr, other)".
+func inspectErrAccumulation(st state, assignStmt *ast.AssignStmt) {
+	if len(st.errObjs.checked) == 0 || len(assignStmt.Lhs) != len(assignStmt.Rhs) {
+		return
+	}
+
+	if commentGroups, ok := st.commentMap[assignStmt]; ok {
+		if checkCommentGroupsForNoLint(commentGroups) {
+			return
+		}
+	}
+
+	for i, lhs := range assignStmt.Lhs {
+		call, _ := ast.Unparen(assignStmt.Rhs[i]).(*ast.CallExpr)
+		if call == nil {
+			continue
+		}
+
+		accumulator := errObjectOf(st.pass, ast.Unparen(lhs))
+		if accumulator == nil || !isErrAccumulation(st.pass, accumulator, call) {
+			continue
+		}
+
+		var added []ast.Expr
+		for _, arg := range getErrArgs(st.pass, call) {
+			if arg != call.Args[0] {
+				added = append(added, arg)
+			}
+		}
+
+		if len(added) == 0 {
+			continue
+		}
+
+		fine, wrongErrs := inspectErrArgs(st, call, added)
+		if fine {
+			continue
+		}
+
+		// The errors are added to the accumulator rather than wrapped.
+		for i := range wrongErrs {
+			if wrongErrs[i].wrapper == call {
+				wrongErrs[i].wrapper = nil
+			}
+		}
+
+		reportSunkWrongErrs(st, errSink{
+			node:     assignStmt,
+			category: categoryWrongAccumulatedError,
+			action:   "appended",
+			target:   "to `" + types.ExprString(lhs) + "`",
+		}, wrongErrs)
+	}
+}
+
+// isErrAccumulation reports whether the call adds errors to the accumulator
+// passed as its first argument, like "append(errs, err)".
+func isErrAccumulation(pass *analysis.Pass, accumulator types.Object, call *ast.CallExpr) bool {
+	if len(call.Args) == 0 || !isErrAggregatorCall(pass, call) {
+		return false
+	}
+
+	return accumulator == errObjectOf(pass, ast.Unparen(call.Args[0]))
+}
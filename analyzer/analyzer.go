@@ -10,7 +10,9 @@ import (
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/analysis/passes/printf"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 const (
@@ -20,25 +22,67 @@ const (
 )
 
 var Analyzer = &analysis.Analyzer{
-	Name:     "correcterr",
-	Doc:      "Checks that the returned error is the one that was checked",
-	Run:      run,
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Name:      "correcterr",
+	Doc:       "Checks that the returned error is the one that was checked",
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer, printf.Analyzer},
+	FactTypes: []analysis.Fact{new(errCheckerFact), new(errFlowFact)},
 }
 
-type stringSet = map[string]struct{}
+// requireWrapVerb enables reporting checked errors that are formatted
+// with %v or %s instead of being wrapped with %w.
+var requireWrapVerb bool
+
+// extraErrSinks and errSinksFile list sink functions besides the default ones, see loadErrSinks.
+var (
+	extraErrSinks sinkSpecs
+	errSinksFile  string
+)
+
+// analysisEngine selects how functions are analyzed, see engineAST and engineSSA.
+var analysisEngine engineName = engineAST
+
+func init() {
+	Analyzer.Flags.BoolVar(&requireWrapVerb, "require-wrap-verb", false,
+		"report checked errors formatted with %v or %s instead of being wrapped with %w")
+	Analyzer.Flags.Var(&extraErrSinks, "sinks",
+		"comma-separated sink functions taking errors, like example.com/api.Respond:1 or (*example.com/api.Span).RecordError:0, where * stands for all the arguments")
+	Analyzer.Flags.StringVar(&errSinksFile, "sinks-file", "",
+		"file listing sink functions in the format of -sinks, one per line")
+	Analyzer.Flags.Var(&analysisEngine, "engine",
+		"analysis engine: ast walks the syntax tree, ssa follows errors through the control flow graph and only inspects return statements")
+}
+
+type objectSet = map[types.Object]struct{}
 
 type state struct {
-	pass       *analysis.Pass
-	errNames   errorNames
-	wraps      map[string]stringSet
-	commentMap ast.CommentMap
+	pass    *analysis.Pass
+	errObjs errorObjects
+	wraps   map[types.Object]objectSet
+	// assignSites holds the latest assignments to variables declared elsewhere.
+	assignSites map[types.Object]*ast.AssignStmt
+	commentMap  ast.CommentMap
+	// namedResults holds the named error results of the enclosing function,
+	// which a bare return statement returns.
+	namedResults []types.Object
+	// deferredResults holds the named results assigned by deferred closures,
+	// which decide what is returned in the end.
+	deferredResults objectSet
+	// assignedResults holds the named results that may have been assigned by now.
+	assignedResults objectSet
+	sinks           errSinkFuncs
+	// condMethodCalls holds the method calls appearing in conditions, see storedErrMethodCallObject.
+	condMethodCalls objectSet
 }
 
-type errorNames struct {
-	funcScope      stringSet
-	immediateScope stringSet
-	checked        stringSet
+type errorObjects struct {
+	funcScope      objectSet
+	immediateScope objectSet
+	// initScope holds the errors declared by the init statement of the statement
+	// being inspected, which belong to the immediate scope of its blocks.
+	initScope objectSet
+	checked   checkSet
+	nils      objectSet
 }
 
 func run(pass *analysis.Pass) (any, error) {
@@ -50,6 +94,22 @@ func run(pass *analysis.Pass) (any, error) {
 		maps.Copy(commentMap, cmap)
 	}
 
+	sinks, err := loadErrSinks()
+	if err != nil {
+		return nil, err
+	}
+
+	exportErrCheckerFacts(pass)
+	exportErrFlowFacts(pass)
+
+	if analysisEngine == engineSSA {
+		runSSA(pass, commentMap)
+
+		return nil, nil
+	}
+
+	condMethodCalls := getCondMethodCalls(pass)
+
 	nodeFilter := []ast.Node{(*ast.FuncDecl)(nil)}
 
 	inspector.Preorder(nodeFilter, func(node ast.Node) {
@@ -60,58 +120,53 @@ func run(pass *analysis.Pass) (any, error) {
 
 		st := state{
 			pass: pass,
-			errNames: errorNames{
-				funcScope:      make(stringSet),
-				checked:        make(stringSet),
-				immediateScope: make(stringSet),
+			errObjs: errorObjects{
+				funcScope:      make(objectSet),
+				checked:        make(checkSet),
+				immediateScope: make(objectSet),
+				nils:           make(objectSet),
 			},
-			wraps:      make(map[string]stringSet),
-			commentMap: commentMap,
+			wraps:           make(map[types.Object]objectSet),
+			commentMap:      commentMap,
+			sinks:           sinks,
+			condMethodCalls: condMethodCalls,
 		}
 
 		if funcNode.Body == nil {
 			return
 		}
 
+		st = st.withNamedResults(funcNode.Type)
+		st = st.withParams(funcNode.Recv, funcNode.Type)
+
 		inspectStatements(st, funcNode.Body.List)
 	})
 
 	return nil, nil
 }
 
-func getLocalErrorNames(statements []ast.Stmt, pass *analysis.Pass) (stringSet, map[string]stringSet) {
-	names := make(stringSet)
-	wraps := make(map[string]stringSet)
+func getLocalErrors(st state, statements []ast.Stmt) objectSet {
+	objs := make(objectSet)
 
 	for _, stmt := range statements {
-		var (
-			nms  []string
-			wrps map[string]stringSet
-		)
+		var declared []types.Object
 
 		switch s := stmt.(type) {
 		case *ast.DeclStmt:
-			nms, wrps = getErrorNamesFromDeclStmt(pass, s)
+			declared, _ = getErrorsFromDeclStmt(st, s)
 		case *ast.AssignStmt:
-			nms, wrps = getErrorNamesFromAssignStmt(pass, s)
+			declared, _ = getErrorsFromAssignStmt(st, s)
 		}
 
-		for _, nm := range nms {
-			names[nm] = struct{}{}
-		}
-		for k, v := range wrps {
-			if wraps[k] == nil {
-				wraps[k] = v
-			} else {
-				maps.Copy(wraps[k], v)
-			}
+		for _, obj := range declared {
+			objs[obj] = struct{}{}
 		}
 	}
 
-	return names, wraps
+	return objs
 }
 
-func getErrorNamesFromDeclStmt(pass *analysis.Pass, decl *ast.DeclStmt) ([]string, map[string]stringSet) {
+func getErrorsFromDeclStmt(st state, decl *ast.DeclStmt) ([]types.Object, map[types.Object]objectSet) {
 	genDecl, _ := decl.Decl.(*ast.GenDecl)
 	if genDecl == nil {
 		return nil, nil
@@ -122,8 +177,8 @@ func getErrorNamesFromDeclStmt(pass *analysis.Pass, decl *ast.DeclStmt) ([]strin
 	}
 
 	var (
-		names []string
-		wraps = make(map[string]stringSet)
+		declared []types.Object
+		wraps    = make(map[types.Object]objectSet)
 	)
 
 	for _, spec := range genDecl.Specs {
@@ -132,123 +187,359 @@ func getErrorNamesFromDeclStmt(pass *analysis.Pass, decl *ast.DeclStmt) ([]strin
 			continue
 		}
 
+		var specObjs []types.Object
 		for _, name := range valSpec.Names {
-			names = append(names, name.Name)
+			if obj := st.pass.TypesInfo.ObjectOf(name); obj != nil {
+				specObjs = append(specObjs, obj)
+			}
 		}
+		declared = append(declared, specObjs...)
 
-		var callErrNames []string
+		var callErrObjs []types.Object
 		for _, expr := range valSpec.Values {
 			switch e := expr.(type) {
 			case *ast.CallExpr:
-				callErrNames = append(callErrNames, scanCallForErrNames(e, pass)...)
+				callErrObjs = append(callErrObjs, scanCallForErrs(st, e)...)
 			case *ast.Ident:
-				if exprIsError(e, pass.TypesInfo) {
-					callErrNames = append(callErrNames, e.Name)
+				if obj := errObjectOf(st.pass, e); obj != nil {
+					callErrObjs = append(callErrObjs, obj)
 				}
-			}
-		}
-		if len(callErrNames) > 0 {
-			for _, name := range valSpec.Names {
-				for _, callErrN := range callErrNames {
-					if wraps[name.Name] == nil {
-						wraps[name.Name] = stringSet{callErrN: struct{}{}}
-					} else {
-						wraps[name.Name][callErrN] = struct{}{}
-					}
+			case *ast.TypeAssertExpr:
+				if obj := typeAssertedErrObject(st.pass, e); obj != nil {
+					callErrObjs = append(callErrObjs, obj)
 				}
 			}
 		}
+		addWraps(wraps, specObjs, callErrObjs)
 	}
 
-	return names, wraps
+	return declared, wraps
 }
 
-func getErrorNamesFromAssignStmt(pass *analysis.Pass, assign *ast.AssignStmt) ([]string, map[string]stringSet) {
-	var names []string
+func getErrorsFromAssignStmt(st state, assign *ast.AssignStmt) ([]types.Object, map[types.Object]objectSet) {
+	var declared []types.Object
 
 	for _, leftExpr := range assign.Lhs {
 		leftIdent, _ := leftExpr.(*ast.Ident)
-		if leftIdent != nil {
-			names = append(names, leftIdent.Name)
+		if leftIdent == nil {
+			continue
+		}
+
+		if obj := st.pass.TypesInfo.ObjectOf(leftIdent); obj != nil {
+			declared = append(declared, obj)
 		}
 	}
 
-	wraps := getErrWrapsFromAssignStmt(pass, assign, names)
+	wraps := getErrWrapsFromAssignStmt(st, assign, declared)
 
-	return names, wraps
+	return declared, wraps
 }
 
-func getErrWrapsFromAssignStmt(pass *analysis.Pass, assign *ast.AssignStmt, names []string) map[string]stringSet {
-	var rightErrNames []string
+func getErrWrapsFromAssignStmt(st state, assign *ast.AssignStmt, assigned []types.Object) map[types.Object]objectSet {
+	var rightErrObjs []types.Object
 
 	for _, rightExpr := range assign.Rhs {
 		switch expr := rightExpr.(type) {
 		case *ast.CallExpr:
-			rightErrNames = append(rightErrNames, scanCallForErrNames(expr, pass)...)
+			rightErrObjs = append(rightErrObjs, scanCallForErrs(st, expr)...)
 		case *ast.Ident:
-			if exprIsError(expr, pass.TypesInfo) {
-				rightErrNames = append(rightErrNames, expr.Name)
+			if obj := errObjectOf(st.pass, expr); obj != nil {
+				rightErrObjs = append(rightErrObjs, obj)
+			}
+		case *ast.TypeAssertExpr:
+			if obj := typeAssertedErrObject(st.pass, expr); obj != nil {
+				rightErrObjs = append(rightErrObjs, obj)
 			}
 		}
 	}
 
-	var wraps = make(map[string]stringSet)
-	if len(rightErrNames) > 0 {
-		for _, errName := range names {
-			for _, callErrN := range rightErrNames {
-				if wraps[errName] == nil {
-					wraps[errName] = stringSet{callErrN: struct{}{}}
-				} else {
-					wraps[errName][callErrN] = struct{}{}
-				}
+	wraps := make(map[types.Object]objectSet)
+	addWraps(wraps, assigned, rightErrObjs)
+
+	return wraps
+}
+
+// typeAssertedErrObject returns the error asserted to a concrete type,
+// like err in "pe, ok := err.(*fs.PathError)", which the result stands for.
+func typeAssertedErrObject(pass *analysis.Pass, assert *ast.TypeAssertExpr) types.Object {
+	return errObjectOf(pass, ast.Unparen(assert.X))
+}
+
+func addWraps(wraps map[types.Object]objectSet, wrappers, wrapped []types.Object) {
+	for _, wrapper := range wrappers {
+		for _, obj := range wrapped {
+			if wraps[wrapper] == nil {
+				wraps[wrapper] = objectSet{obj: struct{}{}}
+			} else {
+				wraps[wrapper][obj] = struct{}{}
 			}
 		}
 	}
+}
 
-	return wraps
+func scanCallForErrs(st state, call *ast.CallExpr) []types.Object {
+	if obj := st.storedErrMethodCallObject(call); obj != nil {
+		return []types.Object{obj}
+	}
+
+	var errObjs []types.Object
+
+	for _, arg := range getErrArgs(st.pass, call) {
+		switch typedArg := arg.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			if obj := errObjectOf(st.pass, typedArg); obj != nil {
+				errObjs = append(errObjs, obj)
+			}
+		case *ast.CallExpr:
+			errObjs = append(errObjs, scanCallForErrs(st, typedArg)...)
+		}
+	}
+
+	return errObjs
 }
 
-func scanCallForErrNames(call *ast.CallExpr, pass *analysis.Pass) []string {
-	var errNames []string
+// getErrArgs returns the error arguments of a call that may flow into its result,
+// including slices of errors, e.g. "errs..." passed to errors.Join.
+// Arguments that the callee is known to drop are skipped, and so are those
+// that a modeled function does not derive its result from, see errFuncModels.
+// Errorf-like calls that wrap errors with %w only wrap those.
+func getErrArgs(pass *analysis.Pass, call *ast.CallExpr) []ast.Expr {
+	if recvCall := getModeledReceiverCall(pass, call); recvCall != nil {
+		return []ast.Expr{recvCall}
+	}
+
+	if fn, model, ok := getErrFuncModel(pass, call); ok {
+		return getModeledErrArgs(pass, fn, model, call)
+	}
+
+	if errArgs, ok := getErrorfErrArgs(pass, call); ok {
+		return errArgs
+	}
+
+	var (
+		errArgs []ast.Expr
+		fact    errFlowFact
+	)
+
+	hasFact := false
+	if fn := typeutil.StaticCallee(pass.TypesInfo, call); fn != nil {
+		hasFact = pass.ImportObjectFact(fn.Origin(), &fact)
+	}
 
-	for _, arg := range call.Args {
-		if !exprIsError(arg, pass.TypesInfo) {
+	for i, arg := range call.Args {
+		if !exprIsError(arg, pass.TypesInfo) && !exprIsErrorSlice(arg, pass.TypesInfo) {
 			continue
 		}
 
-		switch typedArg := arg.(type) {
-		case *ast.Ident:
-			errNames = append(errNames, typedArg.Name)
-		case *ast.CallExpr:
-			errNames = append(errNames, scanCallForErrNames(typedArg, pass)...)
-		case *ast.SelectorExpr:
-			errNames = append(errNames, typedArg.Sel.Name)
+		if hasFact && fact.paramFlow(i) == errFlowDropped {
+			continue
 		}
+
+		errArgs = append(errArgs, arg)
+	}
+
+	return errArgs
+}
+
+// errObjectOf returns the object denoted by an error-typed identifier,
+// selector or method call, or nil if the expression does not refer to
+// a variable of type error. Slices of errors are treated like errors.
+// Fields of local variables and method calls are denoted by their
+// access paths, see fieldPathObject and methodCallObject.
+func errObjectOf(pass *analysis.Pass, expr ast.Expr) types.Object {
+	if !exprIsError(expr, pass.TypesInfo) && !exprIsErrorSlice(expr, pass.TypesInfo) {
+		return nil
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return pass.TypesInfo.ObjectOf(e)
+	case *ast.SelectorExpr:
+		return fieldPathObject(pass, e)
+	case *ast.CallExpr:
+		return methodCallObject(pass, e)
 	}
 
-	return errNames
+	return nil
 }
 
 func inspectStatements(st state, statements []ast.Stmt) {
-	newLocalErrNames, newWraps := getLocalErrorNames(statements, st.pass)
-	if len(newLocalErrNames) > 0 {
-		st.errNames.funcScope = maps.Clone(st.errNames.funcScope)
-		maps.Copy(st.errNames.funcScope, newLocalErrNames)
-
-		st.wraps = cloneStringToStringSetMap(st.wraps)
-		for k, v := range newWraps {
-			if st.wraps[k] == nil {
-				st.wraps[k] = v
+	var newLocalErrs objectSet
+	st, newLocalErrs = st.withDeclarations(statements)
+	st.errObjs.immediateScope = unionSets(newLocalErrs, st.errObjs.initScope)
+	st.errObjs.initScope = nil
+
+	for _, stmt := range statements {
+		assigned := getAssignedObjects(st.pass, stmt)
+		st = st.withoutNils(assigned)
+
+		inspectStatement(st, stmt)
+
+		st = st.withAssignedResults(assigned)
+
+		st = st.withWrapsOf(stmt)
+
+		if assignStmt, ok := stmt.(*ast.AssignStmt); ok {
+			st = st.withAssignSites(assignStmt)
+		}
+
+		if deferStmt, ok := stmt.(*ast.DeferStmt); ok {
+			st = st.withDeferStmt(deferStmt)
+		}
+
+		if ifStmt, ok := stmt.(*ast.IfStmt); ok {
+			if facts := getFactsAfterIfStmt(st.pass, ifStmt); len(facts.checked) > 0 {
+				// Errors declared before the check are not fresh relative to it.
+				st = st.withFacts(facts)
+				st.errObjs.immediateScope = declaredAfter(st.errObjs.immediateScope, ifStmt.End())
+			}
+		}
+	}
+}
+
+// withDeclarations returns a copy of the state that knows about the errors
+// declared by the statements. The declared errors are returned as well.
+// What they wrap is learned statement by statement, see withWrapsOf.
+func (st state) withDeclarations(statements []ast.Stmt) (state, objectSet) {
+	newLocalErrs := getLocalErrors(st, statements)
+
+	return st.withLocalErrors(newLocalErrs), newLocalErrs
+}
+
+// withInitStmt returns a copy of the state that knows about the errors
+// declared by the init statement of an if, for or switch statement.
+// They are visible to the whole statement, and are fresh in its blocks,
+// like the errors declared in the blocks themselves.
+func (st state) withInitStmt(init ast.Stmt) state {
+	st, st.errObjs.initScope = st.withDeclarations([]ast.Stmt{init})
+	st = st.withWrapsOf(init)
+	st = st.withAssignedResults(getAssignedObjects(st.pass, init))
+
+	if assignStmt, ok := init.(*ast.AssignStmt); ok {
+		st = st.withAssignSites(assignStmt)
+	}
+
+	return st
+}
+
+func (st state) withLocalErrors(newLocalErrs objectSet) state {
+	if len(newLocalErrs) == 0 {
+		return st
+	}
+
+	st.errObjs.funcScope = maps.Clone(st.errObjs.funcScope)
+	maps.Copy(st.errObjs.funcScope, newLocalErrs)
+
+	return st
+}
+
+// withWrapsOf returns a copy of the state that knows about the errors wrapped
+// by the values assigned in the statement, which only affects the statements
+// that follow. An assignment or a declaration replaces what the assigned
+// errors wrapped before, while the assignments nested in other statements,
+// which may not be executed, add to it.
+func (st state) withWrapsOf(stmt ast.Stmt) state {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		assigned, wraps := getErrorsFromAssignStmt(st, s)
+		return st.withWraps(assigned, wraps)
+	case *ast.DeclStmt:
+		declared, wraps := getErrorsFromDeclStmt(st, s)
+		return st.withWraps(declared, wraps)
+	case *ast.LabeledStmt:
+		return st.withWrapsOf(s.Stmt)
+	}
+
+	return st.withWraps(nil, getNestedWraps(st, stmt))
+}
+
+// withWraps returns a copy of the state where the assigned errors wrap
+// the errors given by newWraps instead of what they wrapped before,
+// unless they wrap their own previous values, like in
+// "err = fmt.Errorf("read: %w", err)". Errors that are not assigned
+// wrap the given errors in addition to what they wrapped before.
+func (st state) withWraps(assigned []types.Object, newWraps map[types.Object]objectSet) state {
+	if len(assigned) == 0 && len(newWraps) == 0 {
+		return st
+	}
+
+	wraps := cloneWraps(st.wraps)
+
+	for _, obj := range assigned {
+		if _, ok := newWraps[obj][obj]; !ok {
+			delete(wraps, obj)
+		}
+	}
+
+	for k, v := range newWraps {
+		if wraps[k] == nil {
+			wraps[k] = maps.Clone(v)
+		} else {
+			maps.Copy(wraps[k], v)
+		}
+	}
+
+	st.wraps = wraps
+
+	return st
+}
+
+// getNestedWraps returns the errors wrapped by the values assigned anywhere
+// within the node, except for function literals.
+func getNestedWraps(st state, node ast.Node) map[types.Object]objectSet {
+	wraps := make(map[types.Object]objectSet)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		var wrps map[types.Object]objectSet
+
+		switch s := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			_, wrps = getErrorsFromAssignStmt(st, s)
+		case *ast.DeclStmt:
+			_, wrps = getErrorsFromDeclStmt(st, s)
+		}
+
+		for k, v := range wrps {
+			if wraps[k] == nil {
+				wraps[k] = v
 			} else {
-				maps.Copy(st.wraps[k], v)
+				maps.Copy(wraps[k], v)
 			}
 		}
+
+		return true
+	})
+
+	return wraps
+}
+
+// withAssignSites returns a copy of the state that remembers the assignment
+// as the latest one for every variable it assigns but does not declare.
+func (st state) withAssignSites(assignStmt *ast.AssignStmt) state {
+	assignSites := maps.Clone(st.assignSites)
+	if assignSites == nil {
+		assignSites = make(map[types.Object]*ast.AssignStmt)
 	}
-	st.errNames.immediateScope = newLocalErrNames
 
-	for _, stmt := range statements {
-		inspectStatement(st, stmt)
+	for _, lhs := range assignStmt.Lhs {
+		ident, _ := lhs.(*ast.Ident)
+		if ident == nil {
+			continue
+		}
+
+		obj := st.pass.TypesInfo.ObjectOf(ident)
+		if obj == nil || obj.Pos() == ident.Pos() {
+			continue
+		}
+
+		assignSites[obj] = assignStmt
 	}
+
+	st.assignSites = assignSites
+
+	return st
 }
 
 func inspectStatement(st state, stmt ast.Stmt) {
@@ -268,22 +559,74 @@ func inspectStatement(st state, stmt ast.Stmt) {
 	case *ast.DeclStmt:
 		inspectDeclStmt(st, s)
 	case *ast.ReturnStmt:
+		for _, res := range s.Results {
+			inspectExpr(st, res)
+		}
 		inspectReturnStmt(st, s)
+		if requireWrapVerb {
+			inspectUnwrappedCheckedErrs(st, s)
+		}
+	case *ast.TypeSwitchStmt:
+		inspectTypeSwitchStmt(st, s)
+	case *ast.SelectStmt:
+		inspectSelectStmt(st, s)
+	case *ast.BlockStmt:
+		inspectStatements(st, s.List)
+	case *ast.LabeledStmt:
+		inspectStatement(st, s.Stmt)
+	case *ast.GoStmt:
+		inspectCallExpr(st, s.Call)
+	case *ast.DeferStmt:
+		inspectCallExpr(st, s.Call)
+	case *ast.SendStmt:
+		inspectExpr(st, s.Value)
+		inspectErrSinkSend(st, s)
 	}
 }
 
 func inspectIfStmt(st state, ifStmt *ast.IfStmt) {
-	maybeCheckedErr := tryGetCheckedErrFromIfStmt(st.pass, ifStmt)
-	if maybeCheckedErr != nil {
-		st.errNames.checked = maps.Clone(st.errNames.checked)
-		st.errNames.checked[maybeCheckedErr.Name] = struct{}{}
+	if ifStmt.Init != nil {
+		inspectStatement(st, ifStmt.Init)
+		st = st.withInitStmt(ifStmt.Init)
 	}
 
-	inspectStatements(st, ifStmt.Body.List)
+	ifTrue, ifFalse := analyzeCondition(st.pass, ifStmt.Cond)
+
+	inspectStatements(st.withFacts(ifTrue), ifStmt.Body.List)
+
+	switch elseStmt := ifStmt.Else.(type) {
+	case *ast.IfStmt:
+		inspectIfStmt(st.withFacts(ifFalse), elseStmt)
+	case *ast.BlockStmt:
+		inspectStatements(st.withFacts(ifFalse), elseStmt.List)
+	}
 }
 
 func inspectSwitchStmt(st state, switchStmt *ast.SwitchStmt) {
-	for _, stmt := range switchStmt.Body.List {
+	if switchStmt.Init != nil {
+		inspectStatement(st, switchStmt.Init)
+		st = st.withInitStmt(switchStmt.Init)
+	}
+
+	clauseFacts := analyzeSwitchClauses(st.pass, switchStmt)
+
+	for i, stmt := range switchStmt.Body.List {
+		caseClause, _ := stmt.(*ast.CaseClause)
+		if caseClause == nil {
+			continue
+		}
+
+		inspectStatements(st.withFacts(clauseFacts[i]), caseClause.Body)
+	}
+}
+
+func inspectTypeSwitchStmt(st state, typeSwitchStmt *ast.TypeSwitchStmt) {
+	if typeSwitchStmt.Init != nil {
+		inspectStatement(st, typeSwitchStmt.Init)
+		st = st.withInitStmt(typeSwitchStmt.Init)
+	}
+
+	for _, stmt := range typeSwitchStmt.Body.List {
 		caseClause, _ := stmt.(*ast.CaseClause)
 		if caseClause == nil {
 			continue
@@ -293,16 +636,77 @@ func inspectSwitchStmt(st state, switchStmt *ast.SwitchStmt) {
 	}
 }
 
+func inspectSelectStmt(st state, selectStmt *ast.SelectStmt) {
+	for _, stmt := range selectStmt.Body.List {
+		commClause, _ := stmt.(*ast.CommClause)
+		if commClause == nil {
+			continue
+		}
+
+		if commClause.Comm != nil {
+			inspectStatement(st, commClause.Comm)
+		}
+
+		inspectStatements(st, commClause.Body)
+	}
+}
+
 func inspectForStmt(st state, forStmt *ast.ForStmt) {
+	if forStmt.Init != nil {
+		inspectStatement(st, forStmt.Init)
+		st = st.withInitStmt(forStmt.Init)
+	}
+
+	// The body and the condition may run again after any of the assignments
+	// of the body and the post statement.
+	st = st.withWraps(nil, getNestedWraps(st, forStmt.Body))
+	st = st.withAssignedResults(getAssignedObjects(st.pass, forStmt.Body))
+	if forStmt.Post != nil {
+		st = st.withWraps(nil, getNestedWraps(st, forStmt.Post))
+		st = st.withAssignedResults(getAssignedObjects(st.pass, forStmt.Post))
+	}
+
 	inspectStatements(st, forStmt.Body.List)
+
+	if forStmt.Post != nil {
+		inspectStatement(st, forStmt.Post)
+	}
 }
 
 func inspectRangeStmt(st state, rangeStmt *ast.RangeStmt) {
+	st = st.withLocalErrors(getErrorsFromRangeStmt(st.pass, rangeStmt))
+	// The body may run again after any of its assignments.
+	st = st.withWraps(nil, getNestedWraps(st, rangeStmt.Body))
+	st = st.withAssignedResults(getAssignedObjects(st.pass, rangeStmt.Body))
+
 	inspectStatements(st, rangeStmt.Body.List)
 }
 
+// getErrorsFromRangeStmt returns the key and value variables
+// of the range statement that hold errors.
+func getErrorsFromRangeStmt(pass *analysis.Pass, rangeStmt *ast.RangeStmt) objectSet {
+	objs := make(objectSet)
+
+	for _, expr := range []ast.Expr{rangeStmt.Key, rangeStmt.Value} {
+		ident, _ := expr.(*ast.Ident)
+		if ident == nil {
+			continue
+		}
+
+		if obj := errObjectOf(pass, ident); obj != nil {
+			objs[obj] = struct{}{}
+		}
+	}
+
+	return objs
+}
+
 func inspectExprStmt(st state, exprStmt *ast.ExprStmt) {
 	inspectExpr(st, exprStmt.X)
+
+	if call, ok := ast.Unparen(exprStmt.X).(*ast.CallExpr); ok {
+		inspectErrSinkCall(st, exprStmt, call)
+	}
 }
 
 func inspectExpr(st state, expr ast.Expr) {
@@ -311,6 +715,16 @@ func inspectExpr(st state, expr ast.Expr) {
 		inspectCallExpr(st, x)
 	case *ast.FuncLit:
 		inspectFuncLit(st, x)
+	case *ast.ParenExpr:
+		inspectExpr(st, x.X)
+	case *ast.UnaryExpr:
+		inspectExpr(st, x.X)
+	case *ast.CompositeLit:
+		for _, elt := range x.Elts {
+			inspectExpr(st, elt)
+		}
+	case *ast.KeyValueExpr:
+		inspectExpr(st, x.Value)
 	}
 }
 
@@ -326,13 +740,131 @@ func inspectCallExpr(st state, callExpr *ast.CallExpr) {
 }
 
 func inspectFuncLit(st state, funcLit *ast.FuncLit) {
+	st = st.withNamedResults(funcLit.Type)
+	st = st.withParams(nil, funcLit.Type)
+
 	inspectStatements(st, funcLit.Body.List)
 }
 
+// withNamedResults returns a copy of the state for the body of a function
+// of the given type. Its named error results are local to the function.
+func (st state) withNamedResults(funcType *ast.FuncType) state {
+	st.namedResults = nil
+	st.deferredResults = nil
+	st.assignedResults = nil
+
+	if funcType.Results == nil {
+		return st
+	}
+
+	results := make(objectSet)
+	for _, field := range funcType.Results.List {
+		for _, name := range field.Names {
+			obj := st.pass.TypesInfo.ObjectOf(name)
+			if obj == nil || !typeIsError(obj.Type()) {
+				continue
+			}
+
+			st.namedResults = append(st.namedResults, obj)
+			results[obj] = struct{}{}
+		}
+	}
+
+	return st.withLocalErrors(results)
+}
+
+// withParams returns a copy of the state in which the receiver and
+// the parameters of a function are local to it, so that the errors held
+// in their fields, like "res.Err", are scoped like those of local variables.
+// Errors and the fields reached through pointers come from outside
+// of the function, so they are never judged.
+func (st state) withParams(recv *ast.FieldList, funcType *ast.FuncType) state {
+	params := make(objectSet)
+
+	for _, list := range []*ast.FieldList{recv, funcType.Params} {
+		if list == nil {
+			continue
+		}
+
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				obj := st.pass.TypesInfo.ObjectOf(name)
+				if obj == nil || typeIsError(obj.Type()) {
+					continue
+				}
+
+				if _, ok := obj.Type().Underlying().(*types.Pointer); ok {
+					continue
+				}
+
+				params[obj] = struct{}{}
+			}
+		}
+	}
+
+	return st.withLocalErrors(params)
+}
+
+// withAssignedResults returns a copy of the state that knows that the named
+// results among the given variables may have been assigned.
+func (st state) withAssignedResults(assigned objectSet) state {
+	var results objectSet
+
+	for _, obj := range st.namedResults {
+		if _, ok := assigned[obj]; !ok {
+			continue
+		}
+
+		if results == nil {
+			results = maps.Clone(st.assignedResults)
+			if results == nil {
+				results = make(objectSet)
+			}
+		}
+		results[obj] = struct{}{}
+	}
+
+	if results != nil {
+		st.assignedResults = results
+	}
+
+	return st
+}
+
+// withDeferStmt returns a copy of the state that knows about the named results
+// assigned by the deferred call.
+func (st state) withDeferStmt(deferStmt *ast.DeferStmt) state {
+	assigned := getAssignedObjects(st.pass, deferStmt)
+
+	var deferred objectSet
+	for _, obj := range st.namedResults {
+		if _, ok := assigned[obj]; !ok {
+			continue
+		}
+
+		if deferred == nil {
+			deferred = maps.Clone(st.deferredResults)
+			if deferred == nil {
+				deferred = make(objectSet)
+			}
+		}
+		deferred[obj] = struct{}{}
+	}
+
+	if deferred != nil {
+		st.deferredResults = deferred
+	}
+
+	return st
+}
+
 func inspectAssignStmt(st state, assignStmt *ast.AssignStmt) {
 	for _, rightExpr := range assignStmt.Rhs {
 		inspectExpr(st, rightExpr)
 	}
+
+	inspectErrAccumulation(st, assignStmt)
+	inspectErrSinkAssign(st, assignStmt)
 }
 
 func inspectDeclStmt(st state, declStmt *ast.DeclStmt) {
@@ -364,7 +896,22 @@ func inspectReturnStmt(st state, retStmt *ast.ReturnStmt) {
 		}
 	}
 
-	var hasErrors bool
+	if len(retStmt.Results) == 0 {
+		inspectBareReturnStmt(st, retStmt)
+		return
+	}
+
+	for _, res := range retStmt.Results {
+		if _, ok := st.errObjs.nils[errObjectOf(st.pass, res)]; ok {
+			reportNilErr(st, retStmt, res)
+			return
+		}
+	}
+
+	var (
+		hasErrors bool
+		wrongErrs []wrongErr
+	)
 
 	for _, res := range retStmt.Results {
 		if !exprIsError(res, st.pass.TypesInfo) {
@@ -372,139 +919,188 @@ func inspectReturnStmt(st state, retStmt *ast.ReturnStmt) {
 		}
 		hasErrors = true
 
-		switch returnVal := res.(type) {
-
-		case *ast.Ident:
-			if returnedErrIsFine(st, returnVal.Name) {
-				return
-			}
-
-		case *ast.CallExpr:
-			if inspectCall(st, returnVal) {
-				return
-			}
-
-		case *ast.SelectorExpr:
-			if returnedErrIsFine(st, returnVal.Sel.Name) {
-				return
-			}
-
-		default:
+		fine, wrongResErrs := inspectSunkErr(st, res)
+		if fine {
 			return
 		}
+		wrongErrs = append(wrongErrs, wrongResErrs...)
 	}
 
 	if hasErrors {
-		st.pass.Reportf(retStmt.Pos(), "returning not the error that was checked")
+		reportWrongErrs(st, retStmt, wrongErrs)
 	}
 }
 
-func tryGetCheckedErrFromIfStmt(pass *analysis.Pass, ifStmt *ast.IfStmt) *ast.Ident {
-	binaryCondition, _ := ifStmt.Cond.(*ast.BinaryExpr)
-	if binaryCondition == nil {
-		return nil
-	}
+// inspectSunkErr reports whether the error handed over to a sink, like
+// a return statement, is fine. Otherwise, it also returns the wrong errors.
+// Errors that cannot be followed are considered fine.
+func inspectSunkErr(st state, expr ast.Expr) (bool, []wrongErr) {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		if returnedErrIsFine(st, errObjectOf(st.pass, e)) {
+			return true, nil
+		}
 
-	if binaryCondition.Op != token.NEQ {
-		return nil
-	}
+		return false, []wrongErr{{expr: e}}
 
-	if !exprIsError(binaryCondition.X, pass.TypesInfo) {
-		return nil
-	}
+	case *ast.CallExpr:
+		if obj := st.storedErrMethodCallObject(e); obj != nil {
+			if returnedErrIsFine(st, obj) {
+				return true, nil
+			}
 
-	checkedError, ok := binaryCondition.X.(*ast.Ident)
-	if !ok {
-		return nil
-	}
+			return false, []wrongErr{{expr: e}}
+		}
 
-	rightVar, ok := binaryCondition.Y.(*ast.Ident)
-	if !ok {
-		return nil
+		return inspectCall(st, e)
 	}
 
-	if rightVar.Obj != nil {
-		return nil
+	return true, nil
+}
+
+// inspectBareReturnStmt inspects a return statement without results,
+// which returns the named results of the function. Results assigned
+// by deferred closures are not judged, since the closures decide
+// what is returned, while results that have not been assigned yet
+// are nil, like in "return nil".
+func inspectBareReturnStmt(st state, retStmt *ast.ReturnStmt) {
+	var wrongErrs []wrongErr
+
+	for _, obj := range st.namedResults {
+		if _, ok := st.deferredResults[obj]; ok {
+			return
+		}
+
+		if _, ok := st.assignedResults[obj]; !ok {
+			continue
+		}
+
+		if returnedErrIsFine(st, obj) {
+			return
+		}
+		wrongErrs = append(wrongErrs, wrongErr{obj: obj})
 	}
 
-	if rightVar.Name != "nil" {
-		return nil
+	if len(wrongErrs) > 0 {
+		reportWrongErrs(st, retStmt, wrongErrs)
 	}
+}
 
-	return checkedError
+// inspectCall reports whether the error returned by the call is fine.
+// Otherwise, it also returns the wrong errors that the call wraps.
+func inspectCall(st state, call *ast.CallExpr) (bool, []wrongErr) {
+	return inspectErrArgs(st, call, getErrArgs(st.pass, call))
 }
 
-func inspectCall(st state, call *ast.CallExpr) bool {
-	var hasErrors bool
+// inspectErrArgs reports whether any of the given error arguments of the call
+// is fine. Otherwise, it also returns the wrong errors among them.
+func inspectErrArgs(st state, call *ast.CallExpr, errArgs []ast.Expr) (bool, []wrongErr) {
+	var (
+		hasErrors bool
+		wrongErrs []wrongErr
+	)
 
-	for _, arg := range call.Args {
-		if !exprIsError(arg, st.pass.TypesInfo) {
-			continue
-		}
+	for _, arg := range errArgs {
 		hasErrors = true
 
 		switch errArg := arg.(type) {
-		case *ast.Ident:
-			if returnedErrIsFine(st, errArg.Name) {
-				return true
+		case *ast.Ident, *ast.SelectorExpr:
+			if returnedErrIsFine(st, errObjectOf(st.pass, errArg)) {
+				return true, nil
 			}
+			wrongErrs = append(wrongErrs, wrongErr{expr: errArg, wrapper: call})
 		case *ast.CallExpr:
-			if inspectCall(st, errArg) {
-				return true
+			if obj := st.storedErrMethodCallObject(errArg); obj != nil {
+				if returnedErrIsFine(st, obj) {
+					return true, nil
+				}
+				wrongErrs = append(wrongErrs, wrongErr{expr: errArg, wrapper: call})
+
+				continue
 			}
-		case *ast.SelectorExpr:
-			if returnedErrIsFine(st, errArg.Sel.Name) {
-				return true
+
+			fine, wrongCallErrs := inspectCall(st, errArg)
+			if fine {
+				return true, nil
 			}
+			wrongErrs = append(wrongErrs, wrongCallErrs...)
 		}
 	}
 
 	if hasErrors {
-		return false
+		return false, wrongErrs
 	}
 
-	return true
+	return true, nil
 }
 
-func returnedErrIsFine(st state, errName string) bool {
-	if len(st.errNames.checked) == 0 {
+func returnedErrIsFine(st state, obj types.Object) bool {
+	if len(st.errObjs.checked) == 0 {
 		return true
 	}
 
-	return returnedErrIsFineInner(st, errName, make(stringSet))
+	if obj == nil {
+		return true
+	}
+
+	// Errors assigned in place of the checked ones are reported at the assignment.
+	if st.isJudgedAtAssignment(obj) {
+		return true
+	}
+
+	return returnedErrIsFineInner(st, obj, make(objectSet))
 }
 
-func returnedErrIsFineInner(st state, errName string, alreadyChecked stringSet) bool {
-	if _, ok := alreadyChecked[errName]; ok {
+func returnedErrIsFineInner(st state, obj types.Object, alreadyChecked objectSet) bool {
+	if _, ok := alreadyChecked[obj]; ok {
 		return false
 	}
 
-	if len(st.errNames.checked) == 0 {
+	if len(st.errObjs.checked) == 0 {
 		return true
 	}
 
-	if _, ok := st.errNames.checked[errName]; ok {
+	if _, ok := st.errObjs.checked[obj]; ok {
 		return true
 	}
 
-	if _, ok := st.errNames.funcScope[errName]; !ok {
+	// Fields are scoped like the variables they are accessed through.
+	root := pathRoot(obj)
+
+	if _, ok := st.errObjs.funcScope[root]; !ok {
 		return true
 	}
 
-	if _, ok := st.errNames.immediateScope[errName]; ok {
+	// An error declared or assigned in the block of the return statement
+	// is fresh, unless it wraps errors from outside of the block.
+	if _, ok := st.errObjs.immediateScope[root]; ok && !wrapsOuterErrs(st, obj, make(objectSet)) {
 		return true
 	}
 
 	alreadyChecked = maps.Clone(alreadyChecked)
-	alreadyChecked[errName] = struct{}{}
+	alreadyChecked[obj] = struct{}{}
+
+	// Fields of errors, like "serr.Err", hold what the error is made of.
+	if path, ok := obj.(fieldPath); ok && typeIsError(path.root.Type()) {
+		if returnedErrIsFineInner(st, path.root, alreadyChecked) {
+			return true
+		}
+	}
+
+	// Methods of errors, like "merr.ErrorOrNil()", return the error itself.
+	if path, ok := obj.(methodCallPath); ok && typeIsError(path.recv.Type()) {
+		if returnedErrIsFineInner(st, path.recv, alreadyChecked) {
+			return true
+		}
+	}
 
-	wrappedNames, ok := st.wraps[errName]
+	wrappedObjs, ok := st.wraps[obj]
 	if !ok {
 		return false
 	}
 
-	for wrName := range wrappedNames {
-		if returnedErrIsFineInner(st, wrName, alreadyChecked) {
+	for wrapped := range wrappedObjs {
+		if returnedErrIsFineInner(st, wrapped, alreadyChecked) {
 			return true
 		}
 	}
@@ -512,15 +1108,59 @@ func returnedErrIsFineInner(st state, errName string, alreadyChecked stringSet)
 	return false
 }
 
-func exprIsError(v ast.Expr, info *types.Info) bool {
-	if n, ok := info.TypeOf(v).(*types.Named); ok {
-		o := n.Obj()
-		return o != nil && o.Pkg() == nil && o.Name() == "error"
+// wrapsOuterErrs reports whether the error wraps, directly or not,
+// errors declared outside of the immediate scope.
+func wrapsOuterErrs(st state, obj types.Object, visited objectSet) bool {
+	for wrapped := range st.wraps[obj] {
+		if _, ok := visited[wrapped]; ok {
+			continue
+		}
+		visited[wrapped] = struct{}{}
+
+		if _, ok := st.errObjs.immediateScope[pathRoot(wrapped)]; !ok {
+			return true
+		}
+
+		if wrapsOuterErrs(st, wrapped, visited) {
+			return true
+		}
 	}
 
 	return false
 }
 
+func exprIsError(v ast.Expr, info *types.Info) bool {
+	return typeIsError(info.TypeOf(v))
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func exprIsErrorSlice(v ast.Expr, info *types.Info) bool {
+	return typeIsErrorSlice(info.TypeOf(v))
+}
+
+// typeIsError reports whether the type implements the error interface.
+// This covers custom error types and interfaces embedding error,
+// as well as type parameters constrained by them.
+func typeIsError(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	return types.Implements(t, errorType)
+}
+
+// typeIsErrorSlice reports whether the type is a slice of errors, like []error.
+func typeIsErrorSlice(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	slice, ok := t.Underlying().(*types.Slice)
+
+	return ok && typeIsError(slice.Elem())
+}
+
 func checkCommentGroupsForNoLint(commGroups []*ast.CommentGroup) bool {
 	for _, cgroup := range commGroups {
 		for _, comment := range cgroup.List {
@@ -556,8 +1196,20 @@ func checkCommentGroupsForNoLint(commGroups []*ast.CommentGroup) bool {
 	return false
 }
 
-func cloneStringToStringSetMap(m map[string]stringSet) map[string]stringSet {
-	newM := make(map[string]stringSet)
+func declaredAfter(objs objectSet, pos token.Pos) objectSet {
+	filtered := make(objectSet)
+
+	for obj := range objs {
+		if obj.Pos() > pos {
+			filtered[obj] = struct{}{}
+		}
+	}
+
+	return filtered
+}
+
+func cloneWraps(m map[types.Object]objectSet) map[types.Object]objectSet {
+	newM := make(map[types.Object]objectSet)
 
 	for k, v := range m {
 		newM[k] = maps.Clone(v)
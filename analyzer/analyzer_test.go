@@ -1,8 +1,15 @@
 package analyzer
 
 import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	_ "github.com/pkg/errors"
@@ -12,11 +19,225 @@ import (
 func TestAll(t *testing.T) {
 	t.Parallel()
 
+	analysistest.Run(t, getTestdata(t), Analyzer, "pkg")
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	t.Parallel()
+
+	analysistest.RunWithSuggestedFixes(t, getTestdata(t), Analyzer, "fixes")
+}
+
+func TestRequireWrapVerb(t *testing.T) {
+	if err := Analyzer.Flags.Set("require-wrap-verb", "true"); err != nil {
+		t.Fatalf("Failed to set flag: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = Analyzer.Flags.Set("require-wrap-verb", "false")
+	})
+
+	analysistest.RunWithSuggestedFixes(t, getTestdata(t), Analyzer, "wrapverb")
+}
+
+func TestSinks(t *testing.T) {
+	testdata := getTestdata(t)
+
+	flags := map[string]string{
+		"sinks":      "sinks.respond:1",
+		"sinks-file": filepath.Join(testdata, "sinks.txt"),
+	}
+	for name, value := range flags {
+		if err := Analyzer.Flags.Set(name, value); err != nil {
+			t.Fatalf("Failed to set flag %s: %s", name, err)
+		}
+	}
+	t.Cleanup(func() {
+		extraErrSinks = nil
+		errSinksFile = ""
+	})
+
+	analysistest.Run(t, testdata, Analyzer, "sinks")
+}
+
+func TestSSAEngine(t *testing.T) {
+	if err := Analyzer.Flags.Set("engine", engineSSA); err != nil {
+		t.Fatalf("Failed to set flag: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = Analyzer.Flags.Set("engine", engineAST)
+	})
+
+	analysistest.Run(t, getTestdata(t), Analyzer, "ssa")
+}
+
+// TestCompareEngines runs both engines on the main testdata and compares
+// the expectations each of them misses with the recorded ones, so that
+// changes in their precision are noticed. Mismatches are described by
+// the functions they are in, see describeMismatch.
+func TestCompareEngines(t *testing.T) {
+	t.Cleanup(func() {
+		_ = Analyzer.Flags.Set("engine", engineAST)
+	})
+
+	funcs := getTestdataFuncs(t, filepath.Join(getTestdata(t), "src/pkg/err_mistakes.go"))
+
+	testCases := []struct {
+		engine     string
+		mismatches []string
+	}{
+		{engine: engineAST},
+		{
+			engine: engineSSA,
+			mismatches: []string{
+				// Shadowed errors are not told apart by their names.
+				"ShadowedErrReturnedAfterCheck: missing",
+				// The returned error is known to be nil, which is reported instead.
+				"ForInitDeclaredErr: missing",
+				"ForInitDeclaredErr: mismatched",
+				// Errors declared by init statements are judged by where
+				// they are created, rather than as fresh in the blocks.
+				"SwitchInitDeclaredErrIsFresh: unexpected",
+				// Appending to accumulators is not inspected.
+				"AppendedUnrelatedErr: missing",
+				"MultierrAppendedUnrelatedErr: missing",
+				"MultierrorAppendedUnrelatedErr: missing",
+				// Assignments are not inspected, the returns of the assigned
+				// variables are reported instead.
+				"BareReturnAfterAssigningOther: missing",
+				"BareReturnAfterAssigningOther: unexpected",
+				"AssigningOtherBeforeReturn: missing",
+				"AssigningOtherBeforeReturn: unexpected",
+				"AssigningOtherToField: missing",
+				"AssigningLastErrToFirstErr: missing",
+				"AssigningOtherToCapturedVar: missing",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := Analyzer.Flags.Set("engine", tc.engine); err != nil {
+			t.Fatalf("Failed to set flag: %s", err)
+		}
+
+		var rec mismatchRecorder
+		analysistest.Run(&rec, getTestdata(t), Analyzer, "pkg")
+
+		var got []string
+		for _, mismatch := range rec.mismatches {
+			got = append(got, describeMismatch(funcs, mismatch))
+		}
+
+		slices.Sort(got)
+		want := slices.Sorted(slices.Values(tc.mismatches))
+
+		if !slices.Equal(got, want) {
+			t.Errorf("Unexpected mismatches of the %s engine:\ngot:  %q\nwant: %q\n%s",
+				tc.engine, got, want, strings.Join(rec.mismatches, "\n"))
+		}
+	}
+}
+
+// testdataFunc is a function declared in the testdata, by the lines it spans.
+type testdataFunc struct {
+	name       string
+	start, end int
+}
+
+func getTestdataFuncs(t *testing.T, path string) []testdataFunc {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse testdata: %s", err)
+	}
+
+	var funcs []testdataFunc
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			funcs = append(funcs, testdataFunc{
+				name:  funcDecl.Name.Name,
+				start: fset.Position(funcDecl.Pos()).Line,
+				end:   fset.Position(funcDecl.End()).Line,
+			})
+		}
+	}
+
+	return funcs
+}
+
+// describeMismatch describes an error of analysistest, like
+// "pkg/err_mistakes.go:287: no diagnostic was reported matching ...",
+// by the function it is in and its kind, like "ShadowedErrReturnedAfterCheck: missing",
+// which, unlike the position, does not change as the testdata grows.
+func describeMismatch(funcs []testdataFunc, mismatch string) string {
+	pos, message, _ := strings.Cut(mismatch, ": ")
+
+	kind := "mismatched"
+	switch {
+	case strings.HasPrefix(message, "unexpected diagnostic"):
+		kind = "unexpected"
+	case strings.HasPrefix(message, "no diagnostic"):
+		kind = "missing"
+	}
+
+	name := pos
+	if parts := strings.Split(pos, ":"); len(parts) > 1 {
+		line, _ := strconv.Atoi(parts[1])
+		for _, fn := range funcs {
+			if fn.start <= line && line <= fn.end {
+				name = fn.name
+			}
+		}
+	}
+
+	return name + ": " + kind
+}
+
+// mismatchRecorder collects the errors of analysistest instead of failing the test.
+type mismatchRecorder struct {
+	mismatches []string
+}
+
+func (r *mismatchRecorder) Errorf(format string, args ...any) {
+	r.mismatches = append(r.mismatches, fmt.Sprintf(format, args...))
+}
+
+func getTestdata(t *testing.T) string {
+	t.Helper()
+
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Failed to get wd: %s", err)
 	}
 
-	testdata := filepath.Join(filepath.Dir(filepath.Dir(wd)), "correcterr/testdata")
-	analysistest.Run(t, testdata, Analyzer, "pkg")
+	return filepath.Join(filepath.Dir(filepath.Dir(wd)), "correcterr/testdata")
+}
+
+func TestRelatedInformation(t *testing.T) {
+	t.Parallel()
+
+	results := analysistest.Run(t, getTestdata(t), Analyzer, "related")
+	if len(results) != 1 || len(results[0].Diagnostics) != 1 {
+		t.Fatalf("Expected a single diagnostic, got %v", results)
+	}
+
+	fset := results[0].Action.Package.Fset
+
+	var got []string
+	for _, related := range results[0].Diagnostics[0].Related {
+		got = append(got, fmt.Sprintf("%d: %s", fset.Position(related.Pos).Line, related.Message))
+	}
+
+	want := []string{
+		"10: `txErr` checked here",
+		"6: `txErr` declared here",
+		"7: `err` declared here",
+		"8: `err` assigned here",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("Unexpected related information:\ngot:  %q\nwant: %q", got, want)
+	}
 }
@@ -0,0 +1,501 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// checkSet maps the checked errors to the expressions that checked them.
+type checkSet = map[types.Object]ast.Expr
+
+// condFacts describes what is known about errors inside a branch
+// guarded by a condition.
+type condFacts struct {
+	// checked holds the errors the branch is guarded by.
+	checked checkSet
+	// nils holds the errors that are known to be nil inside the branch.
+	nils objectSet
+}
+
+// analyzeCondition returns the facts that hold when cond evaluates
+// to true and to false respectively. Conditions combined with "&&" and "||"
+// accept any of the errors they check, while the errors known to be nil
+// follow the usual rules of boolean logic.
+func analyzeCondition(pass *analysis.Pass, cond ast.Expr) (condFacts, condFacts) {
+	switch c := cond.(type) {
+	case *ast.ParenExpr:
+		return analyzeCondition(pass, c.X)
+
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			ifTrue, ifFalse := analyzeCondition(pass, c.X)
+			return ifFalse, ifTrue
+		}
+
+	case *ast.BinaryExpr:
+		switch c.Op {
+		case token.LAND:
+			xTrue, xFalse := analyzeCondition(pass, c.X)
+			yTrue, yFalse := analyzeCondition(pass, c.Y)
+
+			return bothFacts(xTrue, yTrue), eitherFacts(xFalse, yFalse)
+		case token.LOR:
+			xTrue, xFalse := analyzeCondition(pass, c.X)
+			yTrue, yFalse := analyzeCondition(pass, c.Y)
+
+			return eitherFacts(xTrue, yTrue), bothFacts(xFalse, yFalse)
+		case token.EQL, token.NEQ:
+			return analyzeComparison(pass, c, c.Op, c.X, c.Y)
+		}
+
+	case *ast.CallExpr:
+		return analyzeCallCondition(pass, c)
+	}
+
+	return condFacts{}, condFacts{}
+}
+
+// analyzeComparison recognizes "err != nil" and "err == nil" comparisons,
+// as well as their reversed forms like "nil != err". Comparing errors
+// with each other, e.g. "err == io.EOF", checks them when they are equal.
+// The cond expression is recorded as the site of the check.
+func analyzeComparison(pass *analysis.Pass, cond ast.Expr, op token.Token, x, y ast.Expr) (condFacts, condFacts) {
+	var ifTrue, ifFalse condFacts
+
+	if pass.TypesInfo.Types[x].IsNil() {
+		x, y = y, x
+	}
+
+	if pass.TypesInfo.Types[y].IsNil() {
+		checkedErr := errObjectOf(pass, ast.Unparen(x))
+		if checkedErr == nil {
+			return ifTrue, ifFalse
+		}
+
+		// A method may return an error the next time it is called,
+		// so its result is never known to be nil.
+		var nils objectSet
+		if !isMethodCall(checkedErr) {
+			nils = objectSet{checkedErr: struct{}{}}
+		}
+
+		if op == token.NEQ {
+			ifTrue.checked = checkSet{checkedErr: cond}
+			ifFalse.nils = nils
+		} else {
+			ifTrue.nils = nils
+			ifFalse.checked = checkSet{checkedErr: cond}
+		}
+
+		return ifTrue, ifFalse
+	}
+
+	var checked checkSet
+	for _, expr := range []ast.Expr{x, y} {
+		if obj := errObjectOf(pass, ast.Unparen(expr)); obj != nil {
+			if checked == nil {
+				checked = make(checkSet)
+			}
+			checked[obj] = cond
+		}
+	}
+
+	if op == token.EQL {
+		ifTrue.checked = checked
+	} else {
+		ifFalse.checked = checked
+	}
+
+	return ifTrue, ifFalse
+}
+
+// analyzeSwitchClauses returns the facts that hold inside each clause
+// of the switch statement. A tagged switch compares the tag with every
+// case expression, so "switch err { case nil: }" is the same as
+// "err == nil". Besides its own cases, a clause knows that the cases of
+// the preceding clauses did not match, and the default clause knows that
+// none of the cases matched. A clause that control falls through into
+// may also be entered with the facts of the preceding clause.
+func analyzeSwitchClauses(pass *analysis.Pass, switchStmt *ast.SwitchStmt) []condFacts {
+	clauses := switchStmt.Body.List
+	facts := make([]condFacts, len(clauses))
+	fallenInto := make([]bool, len(clauses))
+
+	var (
+		noneMatched  condFacts
+		defaultIndex = -1
+	)
+
+	for i, stmt := range clauses {
+		caseClause, _ := stmt.(*ast.CaseClause)
+		if caseClause == nil {
+			continue
+		}
+
+		if i+1 < len(clauses) {
+			fallenInto[i+1] = clauseFallsThrough(caseClause)
+		}
+
+		if caseClause.List == nil {
+			defaultIndex = i
+			continue
+		}
+
+		var ifTrue, ifFalse condFacts
+		for j, expr := range caseClause.List {
+			var exprTrue, exprFalse condFacts
+			if switchStmt.Tag != nil {
+				exprTrue, exprFalse = analyzeComparison(pass, expr, token.EQL, switchStmt.Tag, expr)
+			} else {
+				exprTrue, exprFalse = analyzeCondition(pass, expr)
+			}
+
+			if j == 0 {
+				ifTrue, ifFalse = exprTrue, exprFalse
+			} else {
+				ifTrue, ifFalse = eitherFacts(ifTrue, exprTrue), bothFacts(ifFalse, exprFalse)
+			}
+		}
+
+		facts[i] = bothFacts(noneMatched, ifTrue)
+		noneMatched = bothFacts(noneMatched, ifFalse)
+	}
+
+	if defaultIndex >= 0 {
+		facts[defaultIndex] = noneMatched
+	}
+
+	for i := range facts {
+		if fallenInto[i] {
+			facts[i] = eitherFacts(facts[i], facts[i-1])
+		}
+	}
+
+	return facts
+}
+
+func clauseFallsThrough(caseClause *ast.CaseClause) bool {
+	if len(caseClause.Body) == 0 {
+		return false
+	}
+
+	branch, _ := caseClause.Body[len(caseClause.Body)-1].(*ast.BranchStmt)
+
+	return branch != nil && branch.Tok == token.FALLTHROUGH
+}
+
+// bothFacts returns the facts that hold when both x and y hold.
+func bothFacts(x, y condFacts) condFacts {
+	return condFacts{
+		checked: unionSets(x.checked, y.checked),
+		nils:    unionSets(x.nils, y.nils),
+	}
+}
+
+// eitherFacts returns the facts that hold when x or y holds.
+// Either of the checks is acceptable, while an error is only known
+// to be nil if it is nil in both cases.
+func eitherFacts(x, y condFacts) condFacts {
+	return condFacts{
+		checked: unionSets(x.checked, y.checked),
+		nils:    intersectSets(x.nils, y.nils),
+	}
+}
+
+// getFactsAfterIfStmt returns the facts that hold for the statements
+// following an if statement whose body never falls through, e.g.
+// the code after "if err == nil { return nil }" has checked err.
+// Only comparisons with nil carry over, since the rest of the function
+// is not about the errors compared with each other, like "prev != errBusy",
+// or inspected by calls. Errors declared by the init statement of the if
+// statement are out of scope after it, so they are left out.
+func getFactsAfterIfStmt(pass *analysis.Pass, ifStmt *ast.IfStmt) condFacts {
+	if ifStmt.Else != nil || !blockTerminates(ifStmt.Body.List) {
+		return condFacts{}
+	}
+
+	_, ifFalse := analyzeCondition(pass, ifStmt.Cond)
+
+	var checked checkSet
+	for obj, site := range ifFalse.checked {
+		if !isNilComparison(pass, site) {
+			continue
+		}
+
+		if pos := pathRoot(obj).Pos(); pos >= ifStmt.Pos() && pos < ifStmt.End() {
+			continue
+		}
+
+		if checked == nil {
+			checked = make(checkSet)
+		}
+		checked[obj] = site
+	}
+
+	return condFacts{checked: checked}
+}
+
+// isNilComparison reports whether the expression compares a value with nil.
+func isNilComparison(pass *analysis.Pass, expr ast.Expr) bool {
+	binary, _ := expr.(*ast.BinaryExpr)
+
+	return binary != nil && (pass.TypesInfo.Types[binary.X].IsNil() || pass.TypesInfo.Types[binary.Y].IsNil())
+}
+
+// analyzeCallCondition recognizes errors.Is and errors.As conditions,
+// including their counterparts from other libraries, see errFuncModels,
+// as well as calls to functions that have an errCheckerFact.
+// Both the positive and the negated forms count as checking the inspected
+// error, while the target of errors.As is also acceptable when it matched.
+func analyzeCallCondition(pass *analysis.Pass, call *ast.CallExpr) (condFacts, condFacts) {
+	var ifTrue, ifFalse condFacts
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return ifTrue, ifFalse
+	}
+
+	_, model, _ := getErrFuncModel(pass, call)
+	if model.check == errCheckNone {
+		return analyzeErrCheckerCall(pass, fn, call)
+	}
+
+	if len(call.Args) < 2 {
+		return ifTrue, ifFalse
+	}
+
+	inspectedErr := errObjectOf(pass, ast.Unparen(call.Args[0]))
+	if inspectedErr == nil {
+		return ifTrue, ifFalse
+	}
+
+	ifTrue.checked = checkSet{inspectedErr: call}
+	ifFalse.checked = checkSet{inspectedErr: call}
+
+	if model.check == errCheckAs {
+		if target := getErrorsAsTarget(pass, call.Args[1]); target != nil {
+			ifTrue.checked = checkSet{inspectedErr: call, target: call}
+		}
+	}
+
+	return ifTrue, ifFalse
+}
+
+// analyzeErrCheckerCall maps the parameters checked by an error checker
+// function onto the arguments of the call.
+func analyzeErrCheckerCall(pass *analysis.Pass, fn *types.Func, call *ast.CallExpr) (condFacts, condFacts) {
+	var ifTrue, ifFalse condFacts
+
+	var fact errCheckerFact
+	if !pass.ImportObjectFact(fn.Origin(), &fact) {
+		return ifTrue, ifFalse
+	}
+
+	ifTrue.checked = getCheckedArgs(pass, call, fact.CheckedIfTrue)
+	ifFalse.checked = getCheckedArgs(pass, call, fact.CheckedIfFalse)
+
+	return ifTrue, ifFalse
+}
+
+func getCheckedArgs(pass *analysis.Pass, call *ast.CallExpr, indices []int) checkSet {
+	var checked checkSet
+
+	for _, i := range indices {
+		if i >= len(call.Args) {
+			continue
+		}
+
+		obj := errObjectOf(pass, ast.Unparen(call.Args[i]))
+		if obj == nil {
+			continue
+		}
+
+		if checked == nil {
+			checked = make(checkSet)
+		}
+		checked[obj] = call
+	}
+
+	return checked
+}
+
+// getErrorsAsTarget returns the variable that errors.As assigns to,
+// given either "&target" or a pointer variable.
+func getErrorsAsTarget(pass *analysis.Pass, expr ast.Expr) types.Object {
+	expr = ast.Unparen(expr)
+
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = ast.Unparen(unary.X)
+	}
+
+	ident, _ := expr.(*ast.Ident)
+	if ident == nil {
+		return nil
+	}
+
+	return pass.TypesInfo.ObjectOf(ident)
+}
+
+// withFacts returns a copy of the state that takes the facts of a branch
+// into account. An error known to be nil is no longer considered checked.
+func (st state) withFacts(facts condFacts) state {
+	if len(facts.checked) == 0 && len(facts.nils) == 0 {
+		return st
+	}
+
+	st.errObjs.checked = maps.Clone(st.errObjs.checked)
+	st.errObjs.nils = maps.Clone(st.errObjs.nils)
+
+	for obj, site := range facts.checked {
+		st.errObjs.checked[obj] = site
+		delete(st.errObjs.nils, obj)
+	}
+
+	for obj := range facts.nils {
+		st.errObjs.nils[obj] = struct{}{}
+		delete(st.errObjs.checked, obj)
+	}
+
+	return st
+}
+
+// withoutNils returns a copy of the state in which the given errors are
+// no longer known to be nil, e.g. because they have been reassigned.
+// Reassigning a variable also affects the errors held in its fields.
+func (st state) withoutNils(objs objectSet) state {
+	isAssigned := func(obj types.Object) bool {
+		_, ok := objs[obj]
+		if !ok {
+			_, ok = objs[pathRoot(obj)]
+		}
+
+		return ok
+	}
+
+	var found bool
+	for obj := range st.errObjs.nils {
+		if isAssigned(obj) {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return st
+	}
+
+	st.errObjs.nils = maps.Clone(st.errObjs.nils)
+	for obj := range st.errObjs.nils {
+		if isAssigned(obj) {
+			delete(st.errObjs.nils, obj)
+		}
+	}
+
+	return st
+}
+
+// getAssignedObjects returns the variables that are assigned or whose
+// address is taken anywhere within the statement, including closures.
+func getAssignedObjects(pass *analysis.Pass, stmt ast.Stmt) objectSet {
+	assigned := make(objectSet)
+
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				switch l := lhs.(type) {
+				case *ast.Ident:
+					if obj := pass.TypesInfo.ObjectOf(l); obj != nil {
+						assigned[obj] = struct{}{}
+					}
+				case *ast.SelectorExpr:
+					if obj := errObjectOf(pass, l); obj != nil {
+						assigned[obj] = struct{}{}
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			if n.Op != token.AND {
+				return true
+			}
+			if ident, ok := n.X.(*ast.Ident); ok {
+				if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+					assigned[obj] = struct{}{}
+				}
+			}
+		}
+
+		return true
+	})
+
+	return assigned
+}
+
+// blockTerminates reports whether the control never falls through
+// the end of the given statement list.
+func blockTerminates(statements []ast.Stmt) bool {
+	if len(statements) == 0 {
+		return false
+	}
+
+	return stmtTerminates(statements[len(statements)-1])
+}
+
+func stmtTerminates(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.BlockStmt:
+		return blockTerminates(s.List)
+	case *ast.IfStmt:
+		return s.Else != nil && blockTerminates(s.Body.List) && stmtTerminates(s.Else)
+	case *ast.ExprStmt:
+		call, _ := s.X.(*ast.CallExpr)
+		if call == nil {
+			return false
+		}
+		ident, _ := call.Fun.(*ast.Ident)
+
+		return ident != nil && ident.Name == "panic"
+	}
+
+	return false
+}
+
+func unionSets[S ~map[types.Object]V, V any](a, b S) S {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+
+	union := maps.Clone(a)
+	maps.Copy(union, b)
+
+	return union
+}
+
+func intersectSets[S ~map[types.Object]V, V any](a, b S) S {
+	var intersection S
+
+	for obj, v := range a {
+		if _, ok := b[obj]; !ok {
+			continue
+		}
+
+		if intersection == nil {
+			intersection = make(S)
+		}
+		intersection[obj] = v
+	}
+
+	return intersection
+}
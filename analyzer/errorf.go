@@ -0,0 +1,297 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// formatVerb is a verb of a format string along with the index
+// of the operand it formats, e.g. 'w' and 1 for "%[2]w".
+type formatVerb struct {
+	verb    rune
+	operand int
+	// offset is the position of the verb within the format string.
+	offset int
+}
+
+// errorfCall is a call to fmt.Errorf or to a function that behaves like it,
+// with a constant format string.
+type errorfCall struct {
+	call   *ast.CallExpr
+	format ast.Expr
+	// operands holds the arguments following the format string.
+	operands []ast.Expr
+	verbs    []formatVerb
+	// wraps is set if the function wraps the operands formatted with %w,
+	// unlike functions formatting messages with fmt.Sprintf.
+	wraps bool
+}
+
+// getErrorfCall returns the call as an errorfCall if it is a call to fmt.Errorf
+// or to one of its wrappers found by the printf analyzer, and its format
+// string is known. Functions creating errors from messages formatted like
+// fmt.Sprintf, e.g. errors.Errorf of github.com/pkg/errors, see formatsErr,
+// are Errorf-like calls that do not wrap anything.
+func getErrorfCall(pass *analysis.Pass, call *ast.CallExpr) (errorfCall, bool) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || call.Ellipsis.IsValid() {
+		return errorfCall{}, false
+	}
+
+	sig := fn.Signature()
+
+	wraps := true
+	if _, model, ok := getErrFuncModel(pass, call); ok && model.formats {
+		wraps = false
+	} else if fn.FullName() != "fmt.Errorf" {
+		result, _ := pass.ResultOf[printf.Analyzer].(*printf.Result)
+		if result == nil {
+			return errorfCall{}, false
+		}
+
+		switch result.Kind(fn) {
+		case printf.KindErrorf:
+		case printf.KindPrintf:
+			if sig.Results().Len() != 1 || !typeIsError(sig.Results().At(0).Type()) {
+				return errorfCall{}, false
+			}
+			wraps = false
+		default:
+			return errorfCall{}, false
+		}
+	}
+
+	if !sig.Variadic() || sig.Params().Len() < 2 {
+		return errorfCall{}, false
+	}
+
+	formatIndex := sig.Params().Len() - 2
+	if formatIndex >= len(call.Args) {
+		return errorfCall{}, false
+	}
+
+	format := pass.TypesInfo.Types[call.Args[formatIndex]].Value
+	if format == nil || format.Kind() != constant.String {
+		return errorfCall{}, false
+	}
+
+	return errorfCall{
+		call:     call,
+		format:   call.Args[formatIndex],
+		operands: call.Args[formatIndex+1:],
+		verbs:    parseFormatVerbs(constant.StringVal(format)),
+		wraps:    wraps,
+	}, true
+}
+
+// wrappedOperands returns the operands formatted with %w.
+func (c errorfCall) wrappedOperands() []ast.Expr {
+	if !c.wraps {
+		return nil
+	}
+
+	var wrapped []ast.Expr
+
+	for _, v := range c.verbs {
+		if v.verb == 'w' && v.operand < len(c.operands) {
+			wrapped = append(wrapped, c.operands[v.operand])
+		}
+	}
+
+	return wrapped
+}
+
+// getErrorfErrArgs returns the error arguments of an Errorf-like call that are
+// wrapped with %w. If nothing is wrapped, or the call is not an Errorf-like one,
+// false is returned, and all the error arguments should be considered, which
+// are reported as formatted rather than wrapped, see wrongErr.formattedOnly.
+func getErrorfErrArgs(pass *analysis.Pass, call *ast.CallExpr) ([]ast.Expr, bool) {
+	errorf, ok := getErrorfCall(pass, call)
+	if !ok {
+		return nil, false
+	}
+
+	var errArgs []ast.Expr
+	for _, operand := range errorf.wrappedOperands() {
+		if exprIsError(operand, pass.TypesInfo) || exprIsErrorSlice(operand, pass.TypesInfo) {
+			errArgs = append(errArgs, operand)
+		}
+	}
+
+	return errArgs, len(errArgs) > 0
+}
+
+// findCheckedErrFormattedAsText returns the verb formatting a checked error
+// in an Errorf-like call within the node that wraps other errors with %w,
+// e.g. 'v' for `fmt.Errorf("%w: %v", other, err)`.
+func findCheckedErrFormattedAsText(st state, node ast.Node) (rune, bool) {
+	var (
+		verb  rune
+		found bool
+	)
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, _ := n.(*ast.CallExpr)
+		if call == nil || found {
+			return !found
+		}
+
+		errorf, ok := getErrorfCall(st.pass, call)
+		if !ok || len(errorf.wrappedOperands()) == 0 {
+			return true
+		}
+
+		for _, v := range errorf.verbs {
+			if v.verb == 'w' || v.operand >= len(errorf.operands) {
+				continue
+			}
+
+			if _, ok := st.errObjs.checked[errObjectOf(st.pass, ast.Unparen(errorf.operands[v.operand]))]; ok {
+				verb, found = v.verb, true
+				break
+			}
+		}
+
+		return !found
+	})
+
+	return verb, found
+}
+
+// parseFormatVerbs returns the verbs of a printf format string
+// along with the operands they refer to, taking explicit argument
+// indexes like "%[2]w" and operands consumed by "*" into account.
+func parseFormatVerbs(format string) []formatVerb {
+	var (
+		verbs   []formatVerb
+		operand int
+	)
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		operand, i = parseArgIndex(format, i, operand)
+		operand, i = parseNumOrStar(format, i, operand)
+
+		if i < len(format) && format[i] == '.' {
+			i++
+			operand, i = parseArgIndex(format, i, operand)
+			operand, i = parseNumOrStar(format, i, operand)
+		}
+
+		operand, i = parseArgIndex(format, i, operand)
+
+		if i >= len(format) {
+			break
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+
+		if verb == '%' {
+			continue
+		}
+
+		verbs = append(verbs, formatVerb{verb: verb, operand: operand, offset: i - size + 1})
+		operand++
+	}
+
+	return verbs
+}
+
+// parseArgIndex parses an explicit argument index like "[2]" at position i.
+func parseArgIndex(format string, i, operand int) (int, int) {
+	if i >= len(format) || format[i] != '[' {
+		return operand, i
+	}
+
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return operand, i
+	}
+
+	n, err := strconv.Atoi(format[i+1 : i+end])
+	if err != nil || n < 1 {
+		return operand, i + end + 1
+	}
+
+	return n - 1, i + end + 1
+}
+
+// parseNumOrStar skips a width or precision at position i.
+// A "*" consumes an operand.
+func parseNumOrStar(format string, i, operand int) (int, int) {
+	if i < len(format) && format[i] == '*' {
+		return operand + 1, i + 1
+	}
+
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+
+	return operand, i
+}
+
+// inspectUnwrappedCheckedErrs reports Errorf-like calls among the results of the
+// return statement that wrap nothing but format a checked error with %v or %s,
+// breaking the chain for errors.Is and errors.As.
+func inspectUnwrappedCheckedErrs(st state, retStmt *ast.ReturnStmt) {
+	if len(st.errObjs.checked) == 0 {
+		return
+	}
+
+	if retStmtCommentGroup, ok := st.commentMap[retStmt]; ok {
+		if checkCommentGroupsForNoLint(retStmtCommentGroup) {
+			return
+		}
+	}
+
+	for _, res := range retStmt.Results {
+		ast.Inspect(res, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.CallExpr:
+				errorf, ok := getErrorfCall(st.pass, n)
+				if !ok || !errorf.wraps || len(errorf.wrappedOperands()) > 0 {
+					return true
+				}
+
+				if v, ok := errorf.findUnwrappedCheckedErr(st); ok {
+					reportUnwrappedErr(st, errorf, v)
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// findUnwrappedCheckedErr returns the verb formatting a checked error with %v or %s.
+func (c errorfCall) findUnwrappedCheckedErr(st state) (formatVerb, bool) {
+	for _, v := range c.verbs {
+		if (v.verb != 'v' && v.verb != 's') || v.operand >= len(c.operands) {
+			continue
+		}
+
+		if _, ok := st.errObjs.checked[errObjectOf(st.pass, ast.Unparen(c.operands[v.operand]))]; ok {
+			return v, true
+		}
+	}
+
+	return formatVerb{}, false
+}
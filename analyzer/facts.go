@@ -0,0 +1,512 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errCheckerFact is exported for functions whose boolean result is derived
+// from a nil check of some of their error parameters, e.g.
+//
+//	func isRetryable(err error) bool {
+//		return err != nil && !errors.Is(err, context.Canceled)
+//	}
+//
+// A call to such a function is then treated like the nil check itself.
+type errCheckerFact struct {
+	// CheckedIfTrue lists the indices of the parameters that are checked
+	// when the function returns true.
+	CheckedIfTrue []int
+	// CheckedIfFalse lists the indices of the parameters that are checked
+	// when the function returns false.
+	CheckedIfFalse []int
+}
+
+func (*errCheckerFact) AFact() {}
+
+func (f *errCheckerFact) String() string {
+	return fmt.Sprintf("errChecker(true:%v false:%v)", f.CheckedIfTrue, f.CheckedIfFalse)
+}
+
+// exportErrCheckerFacts exports an errCheckerFact for every function of the
+// package that checks its error parameters. Since checkers may call each other,
+// the facts are recomputed until they stop changing.
+func exportErrCheckerFacts(pass *analysis.Pass) {
+	var funcDecls []*ast.FuncDecl
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil {
+				funcDecls = append(funcDecls, funcDecl)
+			}
+		}
+	}
+
+	for range len(funcDecls) + 1 {
+		var changed bool
+
+		for _, funcDecl := range funcDecls {
+			fn, _ := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if fn == nil {
+				continue
+			}
+
+			fact := computeErrCheckerFact(pass, fn, funcDecl.Body)
+			if fact == nil {
+				continue
+			}
+
+			var prev errCheckerFact
+			if pass.ImportObjectFact(fn, &prev) &&
+				slices.Equal(prev.CheckedIfTrue, fact.CheckedIfTrue) &&
+				slices.Equal(prev.CheckedIfFalse, fact.CheckedIfFalse) {
+				continue
+			}
+
+			pass.ExportObjectFact(fn, fact)
+			changed = true
+		}
+
+		if !changed {
+			return
+		}
+	}
+}
+
+func computeErrCheckerFact(pass *analysis.Pass, fn *types.Func, body *ast.BlockStmt) *errCheckerFact {
+	sig := fn.Signature()
+	if sig.Results().Len() != 1 {
+		return nil
+	}
+
+	if basic, ok := sig.Results().At(0).Type().Underlying().(*types.Basic); !ok || basic.Kind() != types.Bool {
+		return nil
+	}
+
+	params := make(map[types.Object]int)
+	for i := range sig.Params().Len() {
+		if param := sig.Params().At(i); typeIsError(param.Type()) {
+			params[param] = i
+		}
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	checkedIfTrue := make(checkSet)
+	checkedIfFalse := make(checkSet)
+
+	// A result is only known to come from a check if every return statement
+	// that may produce it checks some of the parameters, e.g. returning
+	// a constant true leaves nothing checked when the function returns true.
+	alwaysCheckedIfTrue, alwaysCheckedIfFalse := true, true
+
+	for _, retStmt := range getReturnStmts(body) {
+		if len(retStmt.Results) != 1 {
+			continue
+		}
+
+		if value := pass.TypesInfo.Types[retStmt.Results[0]].Value; value != nil && value.Kind() == constant.Bool {
+			if constant.BoolVal(value) {
+				alwaysCheckedIfTrue = false
+			} else {
+				alwaysCheckedIfFalse = false
+			}
+
+			continue
+		}
+
+		ifTrue, ifFalse := analyzeCondition(pass, retStmt.Results[0])
+		if len(paramIndices(params, ifTrue.checked)) == 0 {
+			alwaysCheckedIfTrue = false
+		}
+		if len(paramIndices(params, ifFalse.checked)) == 0 {
+			alwaysCheckedIfFalse = false
+		}
+
+		maps.Copy(checkedIfTrue, ifTrue.checked)
+		maps.Copy(checkedIfFalse, ifFalse.checked)
+	}
+
+	fact := &errCheckerFact{}
+	if alwaysCheckedIfTrue {
+		fact.CheckedIfTrue = paramIndices(params, checkedIfTrue)
+	}
+	if alwaysCheckedIfFalse {
+		fact.CheckedIfFalse = paramIndices(params, checkedIfFalse)
+	}
+
+	if len(fact.CheckedIfTrue) == 0 && len(fact.CheckedIfFalse) == 0 {
+		return nil
+	}
+
+	return fact
+}
+
+func paramIndices[S ~map[types.Object]V, V any](params map[types.Object]int, objs S) []int {
+	var indices []int
+
+	for obj := range objs {
+		if i, ok := params[obj]; ok {
+			indices = append(indices, i)
+		}
+	}
+
+	slices.Sort(indices)
+
+	return indices
+}
+
+// getReturnStmts returns the return statements of a function body,
+// excluding those of nested function literals.
+func getReturnStmts(body *ast.BlockStmt) []*ast.ReturnStmt {
+	var retStmts []*ast.ReturnStmt
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			retStmts = append(retStmts, n)
+		}
+
+		return true
+	})
+
+	return retStmts
+}
+
+// errFlow describes what a function does with one of its parameters.
+type errFlow int
+
+const (
+	// errFlowNone is used for parameters that are not errors.
+	errFlowNone errFlow = iota
+	// errFlowUnknown means that the parameter escapes in a way that is not tracked.
+	errFlowUnknown
+	// errFlowWrapped means that the parameter may flow into the error result.
+	errFlowWrapped
+	// errFlowDropped means that the parameter never reaches the error result.
+	errFlowDropped
+)
+
+func (f errFlow) String() string {
+	switch f {
+	case errFlowUnknown:
+		return "unknown"
+	case errFlowWrapped:
+		return "wrapped"
+	case errFlowDropped:
+		return "dropped"
+	}
+
+	return "none"
+}
+
+// errFlowFact is exported for functions that take errors and return an error
+// but drop at least one of their error parameters, e.g.
+//
+//	func logAndDrop(err error) error {
+//		log.Print(err)
+//		return errors.New("dropped")
+//	}
+//
+// Passing an error to such a parameter is not considered wrapping it.
+type errFlowFact struct {
+	// Params holds the flow of every parameter, indexed by position.
+	Params []errFlow
+}
+
+func (*errFlowFact) AFact() {}
+
+func (f *errFlowFact) String() string {
+	var parts []string
+
+	for i, flow := range f.Params {
+		if flow != errFlowNone {
+			parts = append(parts, fmt.Sprintf("%d:%s", i, flow))
+		}
+	}
+
+	return fmt.Sprintf("errFlow(%s)", strings.Join(parts, " "))
+}
+
+// paramFlow returns the flow of the parameter that receives the i-th argument.
+func (f *errFlowFact) paramFlow(i int) errFlow {
+	if len(f.Params) == 0 {
+		return errFlowUnknown
+	}
+
+	// Variadic arguments are all passed to the last parameter.
+	if i >= len(f.Params) {
+		i = len(f.Params) - 1
+	}
+
+	return f.Params[i]
+}
+
+// exportErrFlowFacts exports an errFlowFact for every function of the package
+// that drops some of its error parameters.
+func exportErrFlowFacts(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			funcDecl, _ := decl.(*ast.FuncDecl)
+			if funcDecl == nil || funcDecl.Body == nil {
+				continue
+			}
+
+			fn, _ := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if fn == nil {
+				continue
+			}
+
+			fact := computeErrFlowFact(pass, fn, funcDecl.Body)
+			if fact == nil || !slices.Contains(fact.Params, errFlowDropped) {
+				continue
+			}
+
+			pass.ExportObjectFact(fn, fact)
+		}
+	}
+}
+
+func computeErrFlowFact(pass *analysis.Pass, fn *types.Func, body *ast.BlockStmt) *errFlowFact {
+	sig := fn.Signature()
+
+	var errResults []types.Object
+	for i := range sig.Results().Len() {
+		if res := sig.Results().At(i); typeIsError(res.Type()) {
+			errResults = append(errResults, res)
+		}
+	}
+
+	if len(errResults) == 0 {
+		return nil
+	}
+
+	fact := &errFlowFact{Params: make([]errFlow, sig.Params().Len())}
+
+	var hasErrParams bool
+	for i := range sig.Params().Len() {
+		if typeIsError(sig.Params().At(i).Type()) {
+			fact.Params[i] = errFlowDropped
+			hasErrParams = true
+		}
+	}
+
+	if !hasErrParams {
+		return nil
+	}
+
+	flows := getObjectsFlowingIntoResults(pass, sig, body, errResults)
+	escaping := getEscapingObjects(pass, body)
+
+	for i, flow := range fact.Params {
+		if flow == errFlowNone {
+			continue
+		}
+
+		param := sig.Params().At(i)
+		if _, ok := flows[param]; ok {
+			fact.Params[i] = errFlowWrapped
+		} else if _, ok := escaping[param]; ok {
+			fact.Params[i] = errFlowUnknown
+		}
+	}
+
+	return fact
+}
+
+// getObjectsFlowingIntoResults returns the variables whose values may
+// end up in one of the error results of the function.
+func getObjectsFlowingIntoResults(
+	pass *analysis.Pass,
+	sig *types.Signature,
+	body *ast.BlockStmt,
+	errResults []types.Object,
+) objectSet {
+	flows := make(objectSet)
+
+	for _, res := range errResults {
+		flows[res] = struct{}{}
+	}
+
+	for _, retStmt := range getReturnStmts(body) {
+		if len(retStmt.Results) != sig.Results().Len() {
+			// A call returning multiple values.
+			for _, res := range retStmt.Results {
+				addMentionedObjects(pass, flows, res)
+			}
+
+			continue
+		}
+
+		for i, res := range retStmt.Results {
+			if typeIsError(sig.Results().At(i).Type()) {
+				addMentionedObjects(pass, flows, res)
+			}
+		}
+	}
+
+	for {
+		size := len(flows)
+
+		ast.Inspect(body, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					if _, ok := flows[getRootObject(pass, lhs)]; ok {
+						for _, rhs := range n.Rhs {
+							addMentionedObjects(pass, flows, rhs)
+						}
+
+						break
+					}
+				}
+
+			case *ast.ValueSpec:
+				for _, name := range n.Names {
+					if _, ok := flows[pass.TypesInfo.ObjectOf(name)]; ok {
+						for _, value := range n.Values {
+							addMentionedObjects(pass, flows, value)
+						}
+
+						break
+					}
+				}
+
+			case *ast.RangeStmt:
+				for _, lhs := range []ast.Expr{n.Key, n.Value} {
+					if lhs == nil {
+						continue
+					}
+
+					if _, ok := flows[getRootObject(pass, lhs)]; ok {
+						addMentionedObjects(pass, flows, n.X)
+					}
+				}
+			}
+
+			return true
+		})
+
+		if len(flows) == size {
+			return flows
+		}
+	}
+}
+
+// getEscapingObjects returns the variables that are used in ways that cannot be
+// followed: captured by closures, having their address taken, stored outside
+// of local variables, sent to channels or passed to methods and function values.
+func getEscapingObjects(pass *analysis.Pass, body *ast.BlockStmt) objectSet {
+	escaping := make(objectSet)
+
+	var stack []ast.Node
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+
+		ident, _ := node.(*ast.Ident)
+		if ident == nil {
+			return true
+		}
+
+		obj, _ := pass.TypesInfo.Uses[ident].(*types.Var)
+		if obj == nil {
+			return true
+		}
+
+		if identEscapes(pass, stack) {
+			escaping[obj] = struct{}{}
+		}
+
+		return true
+	})
+
+	return escaping
+}
+
+// identEscapes inspects the ancestors of an identifier, the last element of the stack.
+func identEscapes(pass *analysis.Pass, stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		child := stack[i+1]
+
+		switch parent := stack[i].(type) {
+		case *ast.FuncLit, *ast.SendStmt, *ast.GoStmt, *ast.DeferStmt:
+			return true
+
+		case *ast.UnaryExpr:
+			if parent.Op == token.AND {
+				return true
+			}
+
+		case *ast.AssignStmt:
+			for _, lhs := range parent.Lhs {
+				ident, _ := ast.Unparen(lhs).(*ast.Ident)
+				if ident == nil {
+					return true
+				}
+
+				if obj := pass.TypesInfo.ObjectOf(ident); obj != nil && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+					return true
+				}
+			}
+
+		case *ast.CallExpr:
+			if child == parent.Fun {
+				continue
+			}
+
+			fn := typeutil.StaticCallee(pass.TypesInfo, parent)
+			if fn == nil || fn.Signature().Recv() != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func addMentionedObjects(pass *analysis.Pass, objs objectSet, expr ast.Expr) {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if obj, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+				objs[obj] = struct{}{}
+			}
+		}
+
+		return true
+	})
+}
+
+// getRootObject returns the variable at the root of expressions
+// like "x", "x.y.z", "x[i]" or "*x".
+func getRootObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return pass.TypesInfo.ObjectOf(e)
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// suggestCheckedErrFixes suggests replacing the wrong error with the checked one.
+// A fix is only suggested when it is unambiguous: exactly one error was checked,
+// exactly one wrong local error is returned, and the checked error is accessible
+// by its name at the place of the wrong one.
+func suggestCheckedErrFixes(st state, wrongErrs []wrongErr) []analysis.SuggestedFix {
+	if len(st.errObjs.checked) != 1 || len(wrongErrs) != 1 {
+		return nil
+	}
+
+	wrongIdent, _ := wrongErrs[0].expr.(*ast.Ident)
+	if wrongIdent == nil {
+		return nil
+	}
+
+	var checked types.Object
+	for obj := range st.errObjs.checked {
+		checked = obj
+	}
+
+	if !objectIsAccessible(st.pass, checked, wrongIdent) {
+		return nil
+	}
+
+	if !types.AssignableTo(checked.Type(), st.pass.TypesInfo.TypeOf(wrongIdent)) {
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Replace %s with %s", wrongIdent.Name, checked.Name()),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     wrongIdent.Pos(),
+			End:     wrongIdent.End(),
+			NewText: []byte(checked.Name()),
+		}},
+	}}
+}
+
+// suggestWrapVerbFixes suggests replacing the verb formatting the checked error with %w.
+// A fix is only suggested when the format string is a literal.
+func suggestWrapVerbFixes(st state, errorf errorfCall, verb formatVerb) []analysis.SuggestedFix {
+	lit, _ := ast.Unparen(errorf.format).(*ast.BasicLit)
+	if lit == nil || lit.Kind != token.STRING {
+		return nil
+	}
+
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+
+	format = format[:verb.offset] + "w" + format[verb.offset+1:]
+
+	newLit := strconv.Quote(format)
+	if strings.HasPrefix(lit.Value, "`") && strconv.CanBackquote(format) {
+		newLit = "`" + format + "`"
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Wrap %s with %%w", types.ExprString(errorf.operands[verb.operand])),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     lit.Pos(),
+			End:     lit.End(),
+			NewText: []byte(newLit),
+		}},
+	}}
+}
+
+// objectIsAccessible reports whether obj can be referred to by its name at the position of ident.
+func objectIsAccessible(pass *analysis.Pass, obj types.Object, ident *ast.Ident) bool {
+	scope := pass.Pkg.Scope().Innermost(ident.Pos())
+	if scope == nil {
+		return false
+	}
+
+	_, found := scope.LookupParent(obj.Name(), ident.Pos())
+
+	return found == obj
+}
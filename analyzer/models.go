@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errCheckKind tells whether a function inspects an error like errors.Is or errors.As.
+type errCheckKind int
+
+const (
+	errCheckNone errCheckKind = iota
+	// errCheckIs is used for functions that compare their first argument
+	// with reference errors, like errors.Is.
+	errCheckIs
+	// errCheckAs is used for functions that look for an error of some type
+	// in the chain of their first argument and assign it to the second one, like errors.As.
+	errCheckAs
+)
+
+// errFuncModel describes how a function of the standard library or of a popular
+// error library handles errors, in place of the generic rule that every error
+// argument of a call is wrapped into its result.
+type errFuncModel struct {
+	// causes lists the indices of the parameters that the result is derived from.
+	// A function creating a fresh error has none.
+	causes []int
+	// aggregates is set for functions that combine several errors into one.
+	aggregates bool
+	// check is set for functions that inspect their first argument.
+	check errCheckKind
+	// formats is set for functions creating errors from messages formatted
+	// like fmt.Sprintf, whose operands are all causes of the result.
+	formats bool
+}
+
+var (
+	freshErr   = errFuncModel{}
+	wrapsErr   = errFuncModel{causes: []int{0}}
+	joinsErr   = errFuncModel{causes: []int{0, 1}, aggregates: true}
+	formatsErr = errFuncModel{formats: true}
+	isErr      = errFuncModel{check: errCheckIs}
+	asErr      = errFuncModel{check: errCheckAs}
+)
+
+// errFuncModels holds the models of functions by package path and name.
+// Functions that may wrap any of their arguments, like fmt.Errorf or
+// xerrors.Errorf with "%w", are left to the generic rule.
+var errFuncModels = map[string]map[string]errFuncModel{
+	"errors": {
+		"New":    freshErr,
+		"Join":   joinsErr,
+		"Unwrap": wrapsErr,
+		"Is":     isErr,
+		"As":     asErr,
+	},
+	"github.com/pkg/errors": {
+		"New":          freshErr,
+		"Errorf":       formatsErr,
+		"Wrap":         wrapsErr,
+		"Wrapf":        wrapsErr,
+		"WithMessage":  wrapsErr,
+		"WithMessagef": wrapsErr,
+		"WithStack":    wrapsErr,
+		"Cause":        wrapsErr,
+		"Unwrap":       wrapsErr,
+		"Is":           isErr,
+		"As":           asErr,
+	},
+	"github.com/cockroachdb/errors": {
+		"New":                freshErr,
+		"NewWithDepth":       freshErr,
+		"Wrap":               wrapsErr,
+		"Wrapf":              wrapsErr,
+		"WrapWithDepth":      wrapsErr,
+		"WrapWithDepthf":     wrapsErr,
+		"WithMessage":        wrapsErr,
+		"WithMessagef":       wrapsErr,
+		"WithStack":          wrapsErr,
+		"WithDetail":         wrapsErr,
+		"WithDetailf":        wrapsErr,
+		"WithHint":           wrapsErr,
+		"WithHintf":          wrapsErr,
+		"WithSecondaryError": wrapsErr,
+		"WithDomain":         wrapsErr,
+		"Mark":               wrapsErr,
+		"Handled":            wrapsErr,
+		"Cause":              wrapsErr,
+		"UnwrapOnce":         wrapsErr,
+		"UnwrapAll":          wrapsErr,
+		"CombineErrors":      joinsErr,
+		"Join":               joinsErr,
+		"Is":                 isErr,
+		"IsAny":              isErr,
+		"As":                 asErr,
+	},
+	"go.uber.org/multierr": {
+		"Append":  joinsErr,
+		"Combine": joinsErr,
+		"Errors":  wrapsErr,
+	},
+	"github.com/hashicorp/go-multierror": {
+		"Append":  joinsErr,
+		"Flatten": wrapsErr,
+	},
+	"golang.org/x/xerrors": {
+		"New":    freshErr,
+		"Opaque": wrapsErr,
+		"Unwrap": wrapsErr,
+		"Is":     isErr,
+		"As":     asErr,
+	},
+	"google.golang.org/grpc/status": {
+		"Error":     freshErr,
+		"Errorf":    formatsErr,
+		"New":       freshErr,
+		"Newf":      freshErr,
+		"FromError": wrapsErr,
+		"Convert":   wrapsErr,
+	},
+}
+
+// getErrFuncModel returns the model of the function called, if there is one.
+func getErrFuncModel(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, errFuncModel, bool) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil || fn.Signature().Recv() != nil {
+		return nil, errFuncModel{}, false
+	}
+
+	model, ok := errFuncModels[fn.Pkg().Path()][fn.Name()]
+
+	return fn, model, ok
+}
+
+// isCause reports whether the parameter the i-th argument of a call to fn
+// is passed to is a cause of the result.
+func (m errFuncModel) isCause(fn *types.Func, i int) bool {
+	sig := fn.Signature()
+
+	// Variadic arguments are all passed to the last parameter.
+	if sig.Variadic() && i >= sig.Params().Len()-1 {
+		if m.formats {
+			return true
+		}
+
+		i = sig.Params().Len() - 1
+	}
+
+	return slices.Contains(m.causes, i)
+}
+
+// getModeledErrArgs returns the arguments of a call to a modeled function
+// that are causes of its result.
+func getModeledErrArgs(pass *analysis.Pass, fn *types.Func, model errFuncModel, call *ast.CallExpr) []ast.Expr {
+	var errArgs []ast.Expr
+
+	for i, arg := range call.Args {
+		if !model.isCause(fn, i) {
+			continue
+		}
+
+		if exprIsError(arg, pass.TypesInfo) || exprIsErrorSlice(arg, pass.TypesInfo) {
+			errArgs = append(errArgs, arg)
+		}
+	}
+
+	return errArgs
+}
+
+// getModeledReceiverCall returns the receiver of a method call if it is
+// a call to a modeled function deriving its result from errors,
+// e.g. status.Convert(err) in "status.Convert(err).Err()".
+func getModeledReceiverCall(pass *analysis.Pass, call *ast.CallExpr) *ast.CallExpr {
+	sel, _ := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if sel == nil {
+		return nil
+	}
+
+	if selection := pass.TypesInfo.Selections[sel]; selection == nil || selection.Kind() != types.MethodVal {
+		return nil
+	}
+
+	recvCall, _ := ast.Unparen(sel.X).(*ast.CallExpr)
+	if recvCall == nil {
+		return nil
+	}
+
+	if _, model, ok := getErrFuncModel(pass, recvCall); !ok || len(model.causes) == 0 {
+		return nil
+	}
+
+	return recvCall
+}
@@ -0,0 +1,243 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fieldPath is an error held in a field of a local variable or a parameter,
+// e.g. "res.Err". It is comparable, so every occurrence of the same access
+// path maps to the same key of an objectSet.
+type fieldPath struct {
+	*types.Var // the field itself
+
+	root   types.Object
+	fields string
+}
+
+// Name returns the access path, like "res.Err".
+func (p fieldPath) Name() string {
+	return p.root.Name() + "." + p.fields
+}
+
+// Pos returns the position of the variable the path is rooted at.
+func (p fieldPath) Pos() token.Pos {
+	return p.root.Pos()
+}
+
+func (p fieldPath) String() string {
+	return p.Name()
+}
+
+// fieldPathObject returns the object standing for the access path
+// of a selector chain like "x.y.err" rooted at a variable or a parameter
+// of the function.
+// Fields reached from package-level variables, function results and
+// the like are not distinguished by the path they are accessed through,
+// so the field itself is returned for them.
+func fieldPathObject(pass *analysis.Pass, sel *ast.SelectorExpr) types.Object {
+	field := pass.TypesInfo.ObjectOf(sel.Sel)
+
+	var fields []string
+	expr := ast.Expr(sel)
+	for {
+		s, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+
+		selection := pass.TypesInfo.Selections[s]
+		if selection == nil || selection.Kind() != types.FieldVal {
+			return field
+		}
+
+		fields = append(fields, s.Sel.Name)
+		expr = ast.Unparen(s.X)
+
+		// Fields are selected through pointers implicitly,
+		// so "(*res).Err" is the same path as "res.Err".
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = ast.Unparen(star.X)
+		}
+	}
+
+	fieldVar, _ := field.(*types.Var)
+	ident, _ := expr.(*ast.Ident)
+	if fieldVar == nil || ident == nil {
+		return field
+	}
+
+	root, _ := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if root == nil || root.Pkg() == nil || root.Parent() == root.Pkg().Scope() {
+		return field
+	}
+
+	for i, j := 0, len(fields)-1; i < j; i, j = i+1, j-1 {
+		fields[i], fields[j] = fields[j], fields[i]
+	}
+
+	return fieldPath{Var: fieldVar, root: root, fields: strings.Join(fields, ".")}
+}
+
+// methodCallPath is an error returned by a method called without arguments
+// on a stable receiver, e.g. "rows.Err()" or "ctx.Err()". Such calls are
+// checked and returned like variables, assuming that they keep returning
+// the same error.
+type methodCallPath struct {
+	*types.Func // the method
+
+	recv types.Object
+}
+
+// Name returns the call, like "rows.Err()".
+func (p methodCallPath) Name() string {
+	return p.recv.Name() + "." + p.Func.Name() + "()"
+}
+
+// Pos returns the position of the receiver.
+func (p methodCallPath) Pos() token.Pos {
+	return p.recv.Pos()
+}
+
+// Type returns the type of the error returned by the method.
+func (p methodCallPath) Type() types.Type {
+	return p.Signature().Results().At(0).Type()
+}
+
+func (p methodCallPath) String() string {
+	return p.Name()
+}
+
+// methodCallObject returns the object standing for an error-returning call
+// of a method without arguments, or nil if the call is not one or its receiver
+// is not a variable or a field of one.
+func methodCallObject(pass *analysis.Pass, call *ast.CallExpr) types.Object {
+	if len(call.Args) != 0 || !exprIsError(call, pass.TypesInfo) {
+		return nil
+	}
+
+	sel, _ := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if sel == nil {
+		return nil
+	}
+
+	selection := pass.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return nil
+	}
+
+	method, _ := selection.Obj().(*types.Func)
+	if method == nil {
+		return nil
+	}
+
+	var recv types.Object
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.Ident:
+		recv, _ = pass.TypesInfo.ObjectOf(x).(*types.Var)
+	case *ast.SelectorExpr:
+		if s := pass.TypesInfo.Selections[x]; s != nil && s.Kind() == types.FieldVal {
+			recv = fieldPathObject(pass, x)
+		}
+	}
+
+	if recv == nil {
+		return nil
+	}
+
+	return methodCallPath{Func: method, recv: recv}
+}
+
+// storedErrMethodCallObject returns the access path of a method call
+// returning an error held by its receiver: a method of an error, like
+// "merr.ErrorOrNil()", or a call that appears in a condition, like
+// "rows.Err()" or "ctx.Err()". Other calls, like "tx.Rollback()",
+// create their errors on the spot, so nil is returned for them.
+func (st state) storedErrMethodCallObject(call *ast.CallExpr) types.Object {
+	path, ok := methodCallObject(st.pass, call).(methodCallPath)
+	if !ok {
+		return nil
+	}
+
+	if typeIsError(path.recv.Type()) {
+		return path
+	}
+
+	if _, ok := st.condMethodCalls[path]; ok {
+		return path
+	}
+
+	return nil
+}
+
+// getCondMethodCalls returns the method calls that appear in the conditions
+// of the if, for and switch statements of the package.
+func getCondMethodCalls(pass *analysis.Pass) objectSet {
+	calls := make(objectSet)
+
+	addCalls := func(cond ast.Expr) {
+		if cond == nil {
+			return
+		}
+
+		ast.Inspect(cond, func(node ast.Node) bool {
+			if call, ok := node.(*ast.CallExpr); ok {
+				if obj := methodCallObject(pass, call); obj != nil {
+					calls[obj] = struct{}{}
+				}
+			}
+
+			return true
+		})
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.IfStmt:
+				addCalls(n.Cond)
+			case *ast.ForStmt:
+				addCalls(n.Cond)
+			case *ast.SwitchStmt:
+				addCalls(n.Tag)
+
+				for _, stmt := range n.Body.List {
+					if caseClause, ok := stmt.(*ast.CaseClause); ok {
+						for _, expr := range caseClause.List {
+							addCalls(expr)
+						}
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	return calls
+}
+
+// isMethodCall reports whether the object stands for a method call,
+// whose result may differ from one call to another.
+func isMethodCall(obj types.Object) bool {
+	_, ok := obj.(methodCallPath)
+
+	return ok
+}
+
+// pathRoot returns the variable an access path is rooted at,
+// or the object itself if it is a plain variable.
+func pathRoot(obj types.Object) types.Object {
+	switch path := obj.(type) {
+	case fieldPath:
+		return path.root
+	case methodCallPath:
+		return pathRoot(path.recv)
+	}
+
+	return obj
+}
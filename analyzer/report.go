@@ -0,0 +1,325 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Diagnostic categories.
+const (
+	// categoryWrongError is used when another error is returned instead of the checked one.
+	categoryWrongError = "wrong-error"
+	// categoryWrongWrappedError is used when another error is wrapped instead of the checked one.
+	categoryWrongWrappedError = "wrong-wrapped-error"
+	// categoryWrongFormattedError is used when another error is only formatted as text,
+	// e.g. with %v in fmt.Errorf, instead of the checked one.
+	categoryWrongFormattedError = "wrong-formatted-error"
+	// categoryLostWrapChain is used when the checked error is formatted as text
+	// while another error is wrapped.
+	categoryLostWrapChain = "lost-wrap-chain"
+	// categoryMessageOnly is used when only the message of the checked error is returned.
+	categoryMessageOnly = "message-only"
+	// categoryNilError is used when the returned error is known to be nil.
+	categoryNilError = "nil-error"
+	// categoryUnwrappedError is used when the checked error is formatted with %v or %s
+	// instead of being wrapped with %w. It is only reported with the -require-wrap-verb flag.
+	categoryUnwrappedError = "unwrapped-error"
+	// categoryWrongAssignedError is used when another error is assigned to a variable
+	// declared outside of the check instead of the checked one.
+	categoryWrongAssignedError = "wrong-assigned-error"
+	// categoryWrongSunkError is used when another error is passed to a sink function,
+	// sent to a channel or panicked with instead of the checked one.
+	categoryWrongSunkError = "wrong-sunk-error"
+	// categoryWrongAccumulatedError is used when another error is appended
+	// to an accumulator of errors instead of the checked one.
+	categoryWrongAccumulatedError = "wrong-accumulated-error"
+)
+
+// wrongErr is an error that is returned in place of the checked one.
+type wrongErr struct {
+	expr ast.Expr
+	// obj is the error returned without an expression, e.g. by a bare return.
+	obj types.Object
+	// wrapper is the call that wraps the error, if any.
+	wrapper *ast.CallExpr
+}
+
+// object returns the variable holding the wrong error, if any.
+func (w wrongErr) object(pass *analysis.Pass) types.Object {
+	if w.obj != nil {
+		return w.obj
+	}
+
+	return errObjectOf(pass, w.expr)
+}
+
+// formattedOnly reports whether the wrapper only formats the error as text,
+// e.g. with %v in fmt.Errorf, instead of wrapping it.
+func (w wrongErr) formattedOnly(pass *analysis.Pass) bool {
+	if w.wrapper == nil || w.expr == nil {
+		return false
+	}
+
+	errorf, ok := getErrorfCall(pass, w.wrapper)
+	if !ok {
+		return false
+	}
+
+	return !slices.ContainsFunc(errorf.wrappedOperands(), func(operand ast.Expr) bool {
+		return ast.Unparen(operand) == ast.Unparen(w.expr)
+	})
+}
+
+func (w wrongErr) String() string {
+	if w.obj != nil {
+		return w.obj.Name()
+	}
+
+	return types.ExprString(w.expr)
+}
+
+func reportWrongErrs(st state, node ast.Node, wrongErrs []wrongErr) {
+	checked := formatObjects(st.errObjs.checked)
+
+	var (
+		category string
+		message  string
+	)
+
+	verb, formattedAsText := findCheckedErrFormattedAsText(st, node)
+
+	switch {
+	case nodeMentionsCheckedErrMessage(st, node):
+		category = categoryMessageOnly
+		message = fmt.Sprintf("checked %s but returned its message only via `.Error()`", checked)
+	case formattedAsText && len(wrongErrs) > 0 && wrongErrs[0].wrapper != nil:
+		category = categoryLostWrapChain
+		message = fmt.Sprintf("checked %s but formatted it with %%%c while wrapping %s in %s",
+			checked, verb, formatWrongErrs(st, wrongErrs), types.ExprString(wrongErrs[0].wrapper.Fun))
+	case len(wrongErrs) > 0 && wrongErrs[0].formattedOnly(st.pass):
+		category = categoryWrongFormattedError
+		message = fmt.Sprintf("checked %s but formatted %s in %s",
+			checked, formatWrongErrs(st, wrongErrs), types.ExprString(wrongErrs[0].wrapper.Fun))
+	case len(wrongErrs) > 0 && wrongErrs[0].wrapper != nil:
+		category = categoryWrongWrappedError
+		message = fmt.Sprintf("checked %s but wrapped %s in %s",
+			checked, formatWrongErrs(st, wrongErrs), types.ExprString(wrongErrs[0].wrapper.Fun))
+	default:
+		category = categoryWrongError
+		message = fmt.Sprintf("checked %s but returned %s", checked, formatWrongErrs(st, wrongErrs))
+	}
+
+	st.pass.Report(analysis.Diagnostic{
+		Pos:            node.Pos(),
+		Category:       category,
+		Message:        message,
+		SuggestedFixes: suggestCheckedErrFixes(st, wrongErrs),
+		Related:        getRelatedInformation(st, wrongErrs),
+	})
+}
+
+func reportSunkWrongErrs(st state, sink errSink, wrongErrs []wrongErr) {
+	message := fmt.Sprintf("checked %s but %s %s", formatObjects(st.errObjs.checked), sink.action, formatSunkErrs(st, wrongErrs))
+	if sink.target != "" {
+		message += " " + sink.target
+	}
+
+	st.pass.Report(analysis.Diagnostic{
+		Pos:            sink.node.Pos(),
+		Category:       sink.category,
+		Message:        message,
+		SuggestedFixes: suggestCheckedErrFixes(st, wrongErrs),
+		Related:        getRelatedInformation(st, wrongErrs),
+	})
+}
+
+func reportUnwrappedErr(st state, errorf errorfCall, verb formatVerb) {
+	operand := errorf.operands[verb.operand]
+
+	st.pass.Report(analysis.Diagnostic{
+		Pos:      errorf.call.Pos(),
+		Category: categoryUnwrappedError,
+		Message: fmt.Sprintf("checked `%s` but formatted it with %%%c instead of wrapping it with %%w",
+			types.ExprString(operand), verb.verb),
+		SuggestedFixes: suggestWrapVerbFixes(st, errorf, verb),
+		Related:        getRelatedInformation(st, nil),
+	})
+}
+
+func reportNilErr(st state, node ast.Node, nilErr ast.Expr) {
+	st.pass.Report(analysis.Diagnostic{
+		Pos:      node.Pos(),
+		Category: categoryNilError,
+		Message:  fmt.Sprintf("returned `%s` which is known to be nil here", types.ExprString(nilErr)),
+		Related:  getObjectSites(st, errObjectOf(st.pass, nilErr)),
+	})
+}
+
+// getRelatedInformation points at the conditions that checked the errors,
+// as well as at the places where the checked and the wrong errors were declared
+// or assigned.
+func getRelatedInformation(st state, wrongErrs []wrongErr) []analysis.RelatedInformation {
+	var related []analysis.RelatedInformation
+
+	checked := sortedObjects(st.errObjs.checked)
+
+	for _, obj := range checked {
+		site := st.errObjs.checked[obj]
+		related = append(related, analysis.RelatedInformation{
+			Pos:     site.Pos(),
+			End:     site.End(),
+			Message: fmt.Sprintf("`%s` checked here", obj.Name()),
+		})
+	}
+
+	for _, obj := range checked {
+		related = append(related, getObjectSites(st, obj)...)
+	}
+
+	seen := make(objectSet)
+	for _, wrong := range wrongErrs {
+		obj := wrong.object(st.pass)
+		if _, ok := seen[obj]; ok || obj == nil {
+			continue
+		}
+		seen[obj] = struct{}{}
+
+		related = append(related, getObjectSites(st, obj)...)
+	}
+
+	return related
+}
+
+// getObjectSites returns the declaration of a variable of the package
+// being analyzed and its latest assignment, if any.
+func getObjectSites(st state, obj types.Object) []analysis.RelatedInformation {
+	if obj == nil {
+		return nil
+	}
+
+	obj = pathRoot(obj)
+
+	if !obj.Pos().IsValid() || obj.Pkg() != st.pass.Pkg {
+		return nil
+	}
+
+	sites := []analysis.RelatedInformation{{
+		Pos:     obj.Pos(),
+		End:     obj.Pos() + token.Pos(len(obj.Name())),
+		Message: fmt.Sprintf("`%s` declared here", obj.Name()),
+	}}
+
+	if assignStmt, ok := st.assignSites[obj]; ok {
+		sites = append(sites, analysis.RelatedInformation{
+			Pos:     assignStmt.Pos(),
+			End:     assignStmt.End(),
+			Message: fmt.Sprintf("`%s` assigned here", obj.Name()),
+		})
+	}
+
+	return sites
+}
+
+func sortedObjects[S ~map[types.Object]V, V any](objs S) []types.Object {
+	sorted := slices.Collect(maps.Keys(objs))
+	slices.SortFunc(sorted, func(a, b types.Object) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+
+	return sorted
+}
+
+// nodeMentionsCheckedErrMessage reports whether the node contains
+// a call to the Error method of a checked error.
+func nodeMentionsCheckedErrMessage(st state, node ast.Node) bool {
+	var found bool
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, _ := n.(*ast.CallExpr)
+		if call == nil || len(call.Args) != 0 {
+			return !found
+		}
+
+		sel, _ := call.Fun.(*ast.SelectorExpr)
+		if sel == nil || sel.Sel.Name != "Error" {
+			return !found
+		}
+
+		if _, ok := st.errObjs.checked[errObjectOf(st.pass, sel.X)]; ok {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// formatObjects formats the names of the objects, sorted, like "`errA` or `errB`".
+func formatObjects[S ~map[types.Object]V, V any](objs S) string {
+	names := make([]string, 0, len(objs))
+	for _, obj := range sortedObjects(objs) {
+		names = append(names, "`"+obj.Name()+"`")
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// formatSunkErrs formats the wrong errors handed over to a sink, mentioning
+// the wrapper, like "`errA` wrapped in fmt.Errorf" or "`errA` formatted in fmt.Errorf".
+func formatSunkErrs(st state, wrongErrs []wrongErr) string {
+	formatted := formatWrongErrs(st, wrongErrs)
+
+	switch {
+	case len(wrongErrs) == 0 || wrongErrs[0].wrapper == nil:
+	case wrongErrs[0].formattedOnly(st.pass):
+		formatted += " formatted in " + types.ExprString(wrongErrs[0].wrapper.Fun)
+	default:
+		formatted += " wrapped in " + types.ExprString(wrongErrs[0].wrapper.Fun)
+	}
+
+	return formatted
+}
+
+// formatWrongErrs formats the wrong errors like "`errA`, `errB`". A wrong error
+// that has the same name as one of the checked errors is called "a different `err`".
+func formatWrongErrs(st state, wrongErrs []wrongErr) string {
+	if len(wrongErrs) == 0 {
+		return "another error"
+	}
+
+	var names []string
+	for _, wrong := range wrongErrs {
+		var simpleName string
+		if wrong.obj != nil {
+			simpleName = wrong.obj.Name()
+		} else if ident, ok := wrong.expr.(*ast.Ident); ok {
+			simpleName = ident.Name
+		}
+
+		name := "`" + wrong.String() + "`"
+
+		if simpleName != "" {
+			for obj := range st.errObjs.checked {
+				if obj.Name() == simpleName {
+					name = "a different " + name
+					break
+				}
+			}
+		}
+
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,342 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errSink is a statement other than return that hands an error over,
+// e.g. an assignment to a variable declared outside of the check,
+// a call to a sink function or a send to a channel.
+type errSink struct {
+	node     ast.Node
+	category string
+	// action and target describe what is done to the wrong errors,
+	// e.g. "assigned" and "to `x`". The target may be empty.
+	action string
+	target string
+}
+
+// inspectErrSinkAssign reports assigning other errors in place of the checked
+// one to variables declared outside of the check, like named results,
+// fields or variables captured by closures. An accumulator of errors
+// is not replaced but appended to, see inspectErrAccumulation.
+func inspectErrSinkAssign(st state, assignStmt *ast.AssignStmt) {
+	if len(st.errObjs.checked) == 0 || len(assignStmt.Lhs) != len(assignStmt.Rhs) {
+		return
+	}
+
+	if commentGroups, ok := st.commentMap[assignStmt]; ok {
+		if checkCommentGroupsForNoLint(commentGroups) {
+			return
+		}
+	}
+
+	for i, lhs := range assignStmt.Lhs {
+		rhs := ast.Unparen(assignStmt.Rhs[i])
+		if !exprIsError(rhs, st.pass.TypesInfo) {
+			continue
+		}
+
+		target := errObjectOf(st.pass, ast.Unparen(lhs))
+		if target == nil || !st.isSinkVar(target) {
+			continue
+		}
+
+		if call, ok := rhs.(*ast.CallExpr); ok && isErrAccumulation(st.pass, target, call) {
+			continue
+		}
+
+		if fine, wrongErrs := inspectSunkErr(st, rhs); !fine {
+			reportSunkWrongErrs(st, errSink{
+				node:     assignStmt,
+				category: categoryWrongAssignedError,
+				action:   "assigned",
+				target:   "to `" + types.ExprString(lhs) + "`",
+			}, wrongErrs)
+		}
+	}
+}
+
+// isSinkVar reports whether the variable outlives the checks of the state,
+// i.e. it is a field, a package-level variable or a local variable declared
+// before the checks.
+func (st state) isSinkVar(obj types.Object) bool {
+	root := pathRoot(obj)
+
+	if v, ok := root.(*types.Var); ok && v.IsField() {
+		return true
+	}
+
+	if root.Pkg() != nil && root.Parent() == root.Pkg().Scope() {
+		return true
+	}
+
+	for _, site := range st.errObjs.checked {
+		if root.Pos() < site.Pos() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isJudgedAtAssignment reports whether the latest value of the variable
+// was assigned after one of the checks, so that the assignment
+// has already been inspected as a sink.
+func (st state) isJudgedAtAssignment(obj types.Object) bool {
+	assignStmt, ok := st.assignSites[obj]
+	if !ok || !st.isSinkVar(obj) {
+		return false
+	}
+
+	for _, site := range st.errObjs.checked {
+		if assignStmt.Pos() > site.Pos() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allArgs stands for all the arguments of a sink function.
+const allArgs = -1
+
+// errSinkFuncs maps the full names of sink functions, like "net/http.Error"
+// or "(*testing.common).Fatal", to the indices of the arguments receiving errors.
+type errSinkFuncs map[string][]int
+
+// defaultErrSinks lists the sink functions of the standard library.
+var defaultErrSinks = []string{
+	"net/http.Error:1",
+	"log.Fatal:*",
+	"log.Fatalf:*",
+	"log.Fatalln:*",
+	"log.Panic:*",
+	"log.Panicf:*",
+	"log.Panicln:*",
+	"(*log.Logger).Fatal:*",
+	"(*log.Logger).Fatalf:*",
+	"(*log.Logger).Fatalln:*",
+	"(*log.Logger).Panic:*",
+	"(*log.Logger).Panicf:*",
+	"(*log.Logger).Panicln:*",
+	"log/slog.Error:*",
+	"log/slog.ErrorContext:*",
+	"(*log/slog.Logger).Error:*",
+	"(*log/slog.Logger).ErrorContext:*",
+	"(*testing.common).Error:*",
+	"(*testing.common).Errorf:*",
+	"(*testing.common).Fatal:*",
+	"(*testing.common).Fatalf:*",
+	"(testing.TB).Error:*",
+	"(testing.TB).Errorf:*",
+	"(testing.TB).Fatal:*",
+	"(testing.TB).Fatalf:*",
+}
+
+// sinkSpecs holds the sink functions given by the -sinks flag,
+// like "example.com/api.Respond:1", where "*" stands for all the arguments.
+type sinkSpecs []string
+
+func (s *sinkSpecs) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *sinkSpecs) Set(value string) error {
+	for _, spec := range strings.Split(value, ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+
+		if _, _, err := parseSinkSpec(spec); err != nil {
+			return err
+		}
+
+		*s = append(*s, spec)
+	}
+
+	return nil
+}
+
+// parseSinkSpec parses a sink function like "net/http.Error:1" or "log.Fatal:*".
+func parseSinkSpec(spec string) (string, int, error) {
+	i := strings.LastIndexByte(spec, ':')
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid sink %q: want name:index", spec)
+	}
+
+	name, index := spec[:i], spec[i+1:]
+	if index == "*" {
+		return name, allArgs, nil
+	}
+
+	n, err := strconv.Atoi(index)
+	if err != nil || n < 0 {
+		return "", 0, fmt.Errorf("invalid sink %q: the index must be a non-negative number or *", spec)
+	}
+
+	return name, n, nil
+}
+
+// loadErrSinks returns the default sink functions along with those given
+// by the -sinks flag and listed in the -sinks-file, one per line.
+// Empty lines and lines starting with "#" in the file are ignored.
+func loadErrSinks() (errSinkFuncs, error) {
+	specs := slices.Concat(defaultErrSinks, extraErrSinks)
+
+	if errSinksFile != "" {
+		content, err := os.ReadFile(errSinksFile)
+		if err != nil {
+			return nil, fmt.Errorf("read sinks file: %w", err)
+		}
+
+		for line := range strings.Lines(string(content)) {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				specs = append(specs, line)
+			}
+		}
+	}
+
+	sinks := make(errSinkFuncs)
+	for _, spec := range specs {
+		name, index, err := parseSinkSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks[name] = append(sinks[name], index)
+	}
+
+	return sinks, nil
+}
+
+// getSinkArgs returns the arguments of a call to a sink function that receive errors.
+// Methods are matched both by the type they are declared on and by the type of
+// the receiver, e.g. both "(*testing.common).Fatal" and "(*testing.T).Fatal".
+func (sinks errSinkFuncs) getSinkArgs(pass *analysis.Pass, call *ast.CallExpr) []ast.Expr {
+	fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if fn == nil {
+		return nil
+	}
+
+	indices := sinks[fn.Origin().FullName()]
+
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if selection := pass.TypesInfo.Selections[sel]; selection != nil && selection.Kind() == types.MethodVal {
+			name := "(" + types.TypeString(selection.Recv(), nil) + ")." + fn.Name()
+			indices = append(slices.Clip(indices), sinks[name]...)
+		}
+	}
+
+	var args []ast.Expr
+	for i, arg := range call.Args {
+		if slices.Contains(indices, allArgs) || slices.Contains(indices, i) {
+			args = append(args, arg)
+		}
+	}
+
+	return args
+}
+
+// getSunkErrs returns the errors handed over by the given expressions,
+// looking into messages like "err.Error()" and calls that are not errors
+// themselves, like fmt.Sprint(err) or slog.Any("err", err).
+func getSunkErrs(pass *analysis.Pass, exprs []ast.Expr) []ast.Expr {
+	var errs []ast.Expr
+
+	for _, expr := range exprs {
+		expr = ast.Unparen(expr)
+
+		if exprIsError(expr, pass.TypesInfo) {
+			errs = append(errs, expr)
+			continue
+		}
+
+		call, _ := expr.(*ast.CallExpr)
+		if call == nil {
+			continue
+		}
+
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && sel.Sel.Name == "Error" && len(call.Args) == 0 &&
+			exprIsError(sel.X, pass.TypesInfo) {
+			errs = append(errs, ast.Unparen(sel.X))
+			continue
+		}
+
+		errs = append(errs, getSunkErrs(pass, call.Args)...)
+	}
+
+	return errs
+}
+
+// inspectErrSinkCall reports handing other errors in place of the checked one
+// to a sink function, like t.Fatal(other), or to panic.
+func inspectErrSinkCall(st state, stmt ast.Stmt, call *ast.CallExpr) {
+	if len(st.errObjs.checked) == 0 {
+		return
+	}
+
+	sink := errSink{node: stmt, category: categoryWrongSunkError}
+
+	var args []ast.Expr
+
+	if ident, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+		if builtin, ok := st.pass.TypesInfo.Uses[ident].(*types.Builtin); ok && builtin.Name() == "panic" {
+			args, sink.action = call.Args, "panicked with"
+		}
+	}
+
+	if sink.action == "" {
+		args = st.sinks.getSinkArgs(st.pass, call)
+		sink.action, sink.target = "passed", "to "+types.ExprString(call.Fun)
+	}
+
+	inspectSunkErrs(st, sink, getSunkErrs(st.pass, args))
+}
+
+// inspectErrSinkSend reports sending other errors in place of the checked one to a channel.
+func inspectErrSinkSend(st state, sendStmt *ast.SendStmt) {
+	if len(st.errObjs.checked) == 0 {
+		return
+	}
+
+	inspectSunkErrs(st, errSink{
+		node:     sendStmt,
+		category: categoryWrongSunkError,
+		action:   "sent",
+		target:   "to `" + types.ExprString(sendStmt.Chan) + "`",
+	}, getSunkErrs(st.pass, []ast.Expr{sendStmt.Value}))
+}
+
+// inspectSunkErrs reports the sink if none of the errors handed over to it is fine.
+func inspectSunkErrs(st state, sink errSink, errs []ast.Expr) {
+	if len(errs) == 0 {
+		return
+	}
+
+	if commentGroups, ok := st.commentMap[sink.node]; ok {
+		if checkCommentGroupsForNoLint(commentGroups) {
+			return
+		}
+	}
+
+	var wrongErrs []wrongErr
+	for _, expr := range errs {
+		fine, wrongExprErrs := inspectSunkErr(st, expr)
+		if fine {
+			return
+		}
+		wrongErrs = append(wrongErrs, wrongExprErrs...)
+	}
+
+	reportSunkWrongErrs(st, sink, wrongErrs)
+}
@@ -0,0 +1,1225 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Analysis engines, see the -engine flag.
+const (
+	// engineAST walks the syntax tree of every function, approximating its control flow.
+	engineAST = "ast"
+	// engineSSA follows the values of errors through the control flow graph
+	// of the SSA form of every function.
+	engineSSA = "ssa"
+)
+
+// engineName is the analysis engine given by the -engine flag.
+type engineName string
+
+func (e *engineName) String() string {
+	return string(*e)
+}
+
+func (e *engineName) Set(value string) error {
+	if value != engineAST && value != engineSSA {
+		return fmt.Errorf("unknown engine %q: want %s or %s", value, engineAST, engineSSA)
+	}
+
+	*e = engineName(value)
+
+	return nil
+}
+
+// ssaBranch is a part of the control flow graph that is only entered through
+// edges where some errors were checked or are known to be nil.
+type ssaBranch struct {
+	// block is the first block of the branch, which dominates the whole branch.
+	block *ssa.BasicBlock
+	// checked holds the checked errors, if they were checked on every edge.
+	checked []ssa.Value
+	// checkedNames names the checked errors after the conditions in the source.
+	checkedNames checkSet
+	// nils holds the errors known to be nil on every edge.
+	nils []ssa.Value
+}
+
+// ssaEdgeFacts describes what is known about errors when a condition
+// takes one of its branches.
+type ssaEdgeFacts struct {
+	checked      []ssa.Value
+	checkedNames checkSet
+	nils         []ssa.Value
+	// nilCheck is set if the errors were checked by a comparison with nil.
+	nilCheck bool
+}
+
+// ssaSyntax maps the positions of SSA instructions to the syntax they originate from.
+type ssaSyntax struct {
+	returns map[token.Pos]*ast.ReturnStmt
+	// conds holds comparisons by the position of the operator, calls by
+	// the position of the opening parenthesis, and the comparisons of
+	// switch tags with case expressions by the position of the latter.
+	conds map[token.Pos]ast.Expr
+}
+
+func newSSASyntax(pass *analysis.Pass) ssaSyntax {
+	syntax := ssaSyntax{
+		returns: make(map[token.Pos]*ast.ReturnStmt),
+		conds:   make(map[token.Pos]ast.Expr),
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.ReturnStmt:
+				syntax.returns[n.Return] = n
+			case *ast.BinaryExpr:
+				syntax.conds[n.OpPos] = n
+			case *ast.CallExpr:
+				syntax.conds[n.Lparen] = n
+			case *ast.SwitchStmt:
+				if n.Tag == nil {
+					break
+				}
+
+				for _, stmt := range n.Body.List {
+					caseClause, _ := stmt.(*ast.CaseClause)
+					if caseClause == nil {
+						continue
+					}
+
+					for _, expr := range caseClause.List {
+						syntax.conds[expr.Pos()] = &ast.BinaryExpr{X: n.Tag, OpPos: expr.Pos(), Op: token.EQL, Y: expr}
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	return syntax
+}
+
+// runSSA inspects the return statements of every function of the package,
+// including function literals, on its SSA form. A returned error is wrong
+// if none of the values it may hold comes from a checked error, a fresh error
+// or an error from outside of the function, while some of them come from
+// local errors that existed before the check.
+func runSSA(pass *analysis.Pass, commentMap ast.CommentMap) {
+	// Packages whose SSA form cannot be built are not inspected, which is
+	// a limitation of the engine rather than a finding in the package.
+	ssaInput, err := buildSSA(pass)
+	if err != nil {
+		return
+	}
+
+	syntax := newSSASyntax(pass)
+
+	for _, fn := range ssaInput.SrcFuncs {
+		inspectSSAFunc(pass, syntax, commentMap, fn)
+	}
+}
+
+// buildSSA builds the SSA form of the package. It is not a requirement of
+// the analyzer, so that the SSA form of every dependency is not built
+// when the ast engine is used. Packages using syntax the SSA builder
+// does not support yet, e.g. from a newer version of the language,
+// panic in the builder, and the panic is returned as an error.
+func buildSSA(pass *analysis.Pass) (ssaInput *buildssa.SSA, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ssaInput, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
+	result, err := buildssa.Analyzer.Run(pass)
+	if err != nil {
+		return nil, err
+	}
+
+	ssaInput, ok := result.(*buildssa.SSA)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result of %s: %T", buildssa.Analyzer.Name, result)
+	}
+
+	return ssaInput, nil
+}
+
+func inspectSSAFunc(pass *analysis.Pass, syntax ssaSyntax, commentMap ast.CommentMap, fn *ssa.Function) {
+	branches := getSSABranches(pass, syntax, fn)
+	if len(branches) == 0 {
+		return
+	}
+
+	for _, block := range fn.Blocks {
+		ret, _ := block.Instrs[len(block.Instrs)-1].(*ssa.Return)
+		if ret == nil {
+			continue
+		}
+
+		retStmt := syntax.returns[ret.Pos()]
+		if retStmt == nil {
+			continue
+		}
+
+		if retStmtCommentGroup, ok := commentMap[retStmt]; ok {
+			if checkCommentGroupsForNoLint(retStmtCommentGroup) {
+				continue
+			}
+		}
+
+		// The branches the return is in are found on its path
+		// in the dominator tree, the innermost one first.
+		var enclosing []*ssaBranch
+		for b := block; b != nil; b = b.Idom() {
+			if branch, ok := branches[b]; ok {
+				enclosing = append(enclosing, branch)
+			}
+		}
+
+		if len(enclosing) > 0 {
+			inspectSSAReturn(pass, fn, ret, retStmt, commentMap, enclosing)
+		}
+	}
+}
+
+// getSSABranches returns the branches of the function by their first blocks.
+// A block entered from several conditions, like the body of
+// "if errA != nil || errB != nil", is a branch if each of them checks errors.
+func getSSABranches(pass *analysis.Pass, syntax ssaSyntax, fn *ssa.Function) map[*ssa.BasicBlock]*ssaBranch {
+	edges := make(map[*ssa.BasicBlock][]ssaEdgeFacts)
+
+	for _, block := range fn.Blocks {
+		ifInstr, _ := block.Instrs[len(block.Instrs)-1].(*ssa.If)
+		if ifInstr == nil || block.Succs[0] == block.Succs[1] {
+			continue
+		}
+
+		ifTrue, ifFalse := getSSAEdgeFacts(pass, syntax, ifInstr.Cond)
+
+		for i, facts := range []ssaEdgeFacts{ifTrue, ifFalse} {
+			succ := block.Succs[i]
+
+			// Only comparisons with nil hold after if statements, and only
+			// as checks, see getFactsAfterIfStmt.
+			if succ.Comment == "if.done" {
+				facts.nils = nil
+				if !facts.nilCheck {
+					facts.checked, facts.checkedNames = nil, nil
+				}
+			}
+
+			edges[succ] = append(edges[succ], facts)
+		}
+	}
+
+	branches := make(map[*ssa.BasicBlock]*ssaBranch)
+
+	for block, blockEdges := range edges {
+		if len(blockEdges) != len(block.Preds) {
+			continue
+		}
+
+		branch := &ssaBranch{block: block, checkedNames: make(checkSet), nils: blockEdges[0].nils}
+		checkedOnEveryEdge := true
+
+		for _, edge := range blockEdges {
+			if len(edge.checked) == 0 {
+				checkedOnEveryEdge = false
+			}
+			branch.checked = append(branch.checked, edge.checked...)
+			maps.Copy(branch.checkedNames, edge.checkedNames)
+
+			branch.nils = slices.DeleteFunc(slices.Clone(branch.nils), func(v ssa.Value) bool {
+				return !slices.Contains(edge.nils, v)
+			})
+		}
+
+		if !checkedOnEveryEdge {
+			branch.checked, branch.checkedNames = nil, nil
+		}
+
+		if len(branch.checked) > 0 || len(branch.nils) > 0 {
+			branches[block] = branch
+		}
+	}
+
+	return branches
+}
+
+// getSSAEdgeFacts returns what is known about errors when the condition holds
+// and when it does not. The errors are named after the condition in the source,
+// and are only considered if it names them. Negations and the operands
+// of "&&" and "||" are conditions of their own in the SSA form.
+func getSSAEdgeFacts(pass *analysis.Pass, syntax ssaSyntax, cond ssa.Value) (ssaEdgeFacts, ssaEdgeFacts) {
+	if c, ok := cond.(*ssa.UnOp); ok && c.Op == token.NOT {
+		ifTrue, ifFalse := getSSAEdgeFacts(pass, syntax, c.X)
+		return ifFalse, ifTrue
+	}
+
+	var (
+		ifTrue, ifFalse ssaEdgeFacts
+		pos             token.Pos
+	)
+
+	switch c := cond.(type) {
+	case *ssa.BinOp:
+		if c.Op != token.EQL && c.Op != token.NEQ {
+			break
+		}
+		pos = c.Pos()
+
+		x, y := c.X, c.Y
+		if isNilConst(x) {
+			x, y = y, x
+		}
+
+		if !typeIsError(x.Type()) {
+			break
+		}
+
+		var equal, notEqual *ssaEdgeFacts
+		if c.Op == token.EQL {
+			equal, notEqual = &ifTrue, &ifFalse
+		} else {
+			equal, notEqual = &ifFalse, &ifTrue
+		}
+
+		if isNilConst(y) {
+			notEqual.checked = []ssa.Value{x}
+			notEqual.nilCheck = true
+			equal.nils = []ssa.Value{x}
+		} else {
+			equal.checked = []ssa.Value{x, y}
+		}
+
+	case *ssa.Call:
+		pos = c.Pos()
+		ifTrue.checked, ifFalse.checked = getSSACheckedArgs(pass, c.Common())
+	}
+
+	expr := syntax.conds[pos]
+	if expr == nil {
+		return ssaEdgeFacts{}, ssaEdgeFacts{}
+	}
+
+	namesTrue, namesFalse := analyzeCondition(pass, expr)
+
+	ifTrue.checkedNames = namesTrue.checked
+	if len(ifTrue.checkedNames) == 0 {
+		ifTrue.checked = nil
+	}
+
+	ifFalse.checkedNames = namesFalse.checked
+	if len(ifFalse.checkedNames) == 0 {
+		ifFalse.checked = nil
+	}
+
+	return ifTrue, ifFalse
+}
+
+// getSSACheckedArgs returns the errors inspected by a call to a function like
+// errors.Is or to a function that has an errCheckerFact, when the call returns
+// true and false. The target of errors.As holds the inspected error if it returns true.
+func getSSACheckedArgs(pass *analysis.Pass, common *ssa.CallCommon) ([]ssa.Value, []ssa.Value) {
+	fn := staticCalleeObject(common)
+	if fn == nil {
+		return nil, nil
+	}
+
+	if model, ok := getSSAErrFuncModel(fn); ok && model.check != errCheckNone {
+		if len(common.Args) < 2 {
+			return nil, nil
+		}
+
+		inspected := common.Args[:1]
+		if model.check != errCheckAs {
+			return inspected, inspected
+		}
+
+		return append(slices.Clip(inspected), getSSALoads(unwrapSSAInterface(common.Args[1]))...), inspected
+	}
+
+	var fact errCheckerFact
+	if !pass.ImportObjectFact(fn.Origin(), &fact) {
+		return nil, nil
+	}
+
+	args := callArgs(common)
+
+	return selectSSAArgs(args, fact.CheckedIfTrue), selectSSAArgs(args, fact.CheckedIfFalse)
+}
+
+func selectSSAArgs(args []ssa.Value, indices []int) []ssa.Value {
+	var selected []ssa.Value
+	for _, i := range indices {
+		if i < len(args) {
+			selected = append(selected, args[i])
+		}
+	}
+
+	return selected
+}
+
+// getSSALoads returns the loads of the variable at the address.
+func getSSALoads(addr ssa.Value) []ssa.Value {
+	refs := addr.Referrers()
+	if refs == nil {
+		return nil
+	}
+
+	var loads []ssa.Value
+	for _, ref := range *refs {
+		if load, ok := ref.(*ssa.UnOp); ok && load.Op == token.MUL {
+			loads = append(loads, load)
+		}
+	}
+
+	return loads
+}
+
+// inspectSSAReturn reports returning errors known to be nil, and, within
+// checks, errors other than the checked ones, see runSSA.
+func inspectSSAReturn(
+	pass *analysis.Pass,
+	fn *ssa.Function,
+	ret *ssa.Return,
+	retStmt *ast.ReturnStmt,
+	commentMap ast.CommentMap,
+	enclosing []*ssaBranch,
+) {
+	st := state{
+		pass:       pass,
+		errObjs:    errorObjects{checked: make(checkSet)},
+		commentMap: commentMap,
+	}
+
+	var (
+		checked []ssa.Value
+		nils    []ssa.Value
+		region  *ssa.BasicBlock
+	)
+
+	for _, branch := range enclosing {
+		nils = append(nils, branch.nils...)
+
+		// Errors declared in the init statements of if statements are out
+		// of scope after them, e.g. after an early return when they are nil.
+		if !anyInScope(branch.checkedNames, retStmt.Pos()) {
+			continue
+		}
+
+		if region == nil {
+			region = branch.block
+		}
+		checked = append(checked, branch.checked...)
+		maps.Copy(st.errObjs.checked, branch.checkedNames)
+	}
+
+	// The results are only named after the source if they are returned explicitly.
+	var results []ast.Expr
+	if len(retStmt.Results) == len(ret.Results) {
+		results = retStmt.Results
+	}
+
+	for i := range results {
+		if values := getSSAReturnedValues(ret, i); len(values) == 1 && slices.Contains(nils, values[0]) {
+			reportNilErr(st, retStmt, results[i])
+			return
+		}
+	}
+
+	if region == nil {
+		return
+	}
+
+	if requireWrapVerb {
+		inspectUnwrappedCheckedErrs(st, retStmt)
+	}
+
+	if wrongErrs, ok := getSSAWrongErrs(pass, fn, ret, results, checked, region); ok {
+		reportWrongErrs(st, retStmt, wrongErrs)
+	}
+}
+
+// anyInScope reports whether any of the checked errors is in scope at the position.
+func anyInScope(checked checkSet, pos token.Pos) bool {
+	for obj := range checked {
+		scope := pathRoot(obj).Parent()
+		if scope == nil || !scope.Pos().IsValid() || scope.Contains(pos) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSSAWrongErrs returns the errors returned in place of the checked ones,
+// and false if any of the returned errors is fine or none of them is wrong.
+func getSSAWrongErrs(
+	pass *analysis.Pass,
+	fn *ssa.Function,
+	ret *ssa.Return,
+	results []ast.Expr,
+	checked []ssa.Value,
+	region *ssa.BasicBlock,
+) ([]wrongErr, bool) {
+	var wrongErrs []wrongErr
+
+	condCalls := getSSACondCalls(fn)
+
+	for i, res := range ret.Results {
+		if !typeIsError(res.Type()) {
+			continue
+		}
+
+		tracer := ssaErrTracer{
+			pass:      pass,
+			checked:   checked,
+			condCalls: condCalls,
+			region:    region,
+			visited:   make(map[ssa.Value]struct{}),
+		}
+
+		fine, wrong := tracer.traceAll(getSSAReturnedValues(ret, i))
+		switch {
+		case fine:
+			return nil, false
+		case !wrong && (results == nil || pass.TypesInfo.Types[results[i]].IsNil()):
+		// Errors that may only be nil, like a variable declared but never
+		// assigned, are returned in place of the checked ones as well.
+		case results != nil:
+			wrongErrs = append(wrongErrs, getReturnedErrs(pass, results[i])...)
+		default:
+			wrongErrs = append(wrongErrs, wrongErr{obj: fn.Signature.Results().At(i)})
+		}
+	}
+
+	return wrongErrs, len(wrongErrs) > 0
+}
+
+// getSSAReturnedValues returns the values the i-th result of the return may hold.
+// In functions with defer statements, the results are stored into slots before
+// the deferred calls run and are loaded back afterwards, so the value stored
+// by the return itself is returned, along with the free variables of deferred
+// closures that may assign the slot, like errors from outside of the function.
+// The slots of named results are loaded as they are by bare returns.
+func getSSAReturnedValues(ret *ssa.Return, i int) []ssa.Value {
+	res := ret.Results[i]
+
+	load, _ := res.(*ssa.UnOp)
+	if load == nil || load.Op != token.MUL {
+		return []ssa.Value{res}
+	}
+
+	slot, _ := load.X.(*ssa.Alloc)
+	if slot == nil {
+		return []ssa.Value{res}
+	}
+
+	var stored ssa.Value
+	for _, instr := range ret.Block().Instrs {
+		if instr == load {
+			break
+		}
+
+		if store, ok := instr.(*ssa.Store); ok && store.Addr == slot {
+			stored = store.Val
+		}
+	}
+
+	if stored == nil {
+		return []ssa.Value{res}
+	}
+
+	values := []ssa.Value{stored}
+
+	for _, ref := range *slot.Referrers() {
+		makeClosure, _ := ref.(*ssa.MakeClosure)
+		if makeClosure == nil || !isDeferredClosure(makeClosure) {
+			continue
+		}
+
+		closure, _ := makeClosure.Fn.(*ssa.Function)
+		if closure == nil {
+			continue
+		}
+
+		for j, binding := range makeClosure.Bindings {
+			if binding != slot {
+				continue
+			}
+
+			if closureStored, ok := getStoresTo(closure.FreeVars[j], nil, closure); !ok || len(closureStored) > 0 {
+				values = append(values, closure.FreeVars[j])
+			}
+		}
+	}
+
+	return values
+}
+
+// getReturnedErrs describes the errors returned by the expression,
+// like `other` or `other` wrapped in fmt.Errorf.
+func getReturnedErrs(pass *analysis.Pass, expr ast.Expr) []wrongErr {
+	call, _ := ast.Unparen(expr).(*ast.CallExpr)
+	if call == nil || methodCallObject(pass, call) != nil {
+		return []wrongErr{{expr: ast.Unparen(expr)}}
+	}
+
+	var wrongErrs []wrongErr
+
+	for _, arg := range getErrArgs(pass, call) {
+		if argCall, ok := ast.Unparen(arg).(*ast.CallExpr); ok && methodCallObject(pass, argCall) == nil {
+			wrongErrs = append(wrongErrs, getReturnedErrs(pass, argCall)...)
+			continue
+		}
+
+		wrongErrs = append(wrongErrs, wrongErr{expr: ast.Unparen(arg), wrapper: call})
+	}
+
+	return wrongErrs
+}
+
+// ssaErrTracer follows an error back to the values it may come from.
+type ssaErrTracer struct {
+	pass    *analysis.Pass
+	checked []ssa.Value
+	// condCalls holds the method calls appearing in the conditions of the function.
+	condCalls []*ssa.Call
+	// region is the first block of the innermost check. Errors created
+	// within it are fresh.
+	region  *ssa.BasicBlock
+	visited map[ssa.Value]struct{}
+}
+
+// trace reports whether the error may come from a checked error, a fresh one
+// or one from outside of the function, and whether it may come from a local
+// error that existed before the innermost check. Nil errors are neither.
+func (t ssaErrTracer) trace(v ssa.Value) (bool, bool) {
+	if _, ok := t.visited[v]; ok {
+		return false, false
+	}
+	t.visited[v] = struct{}{}
+
+	if t.isChecked(v) {
+		return true, false
+	}
+
+	switch v := v.(type) {
+	case *ssa.Const, *ssa.MakeSlice:
+		// Slices of errors are made empty.
+		return false, false
+	case *ssa.Parameter, *ssa.FreeVar, *ssa.Global, *ssa.Function, *ssa.Builtin:
+		return true, false
+	case *ssa.Phi:
+		return t.traceAll(v.Edges)
+	case *ssa.MakeInterface:
+		return t.trace(v.X)
+	case *ssa.ChangeInterface:
+		return t.trace(v.X)
+	case *ssa.ChangeType:
+		return t.trace(v.X)
+	case *ssa.Field:
+		return t.trace(v.X)
+	case *ssa.TypeAssert:
+		if typeIsError(v.X.Type()) {
+			return t.trace(v.X)
+		}
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			break
+		}
+
+		// Fields of errors, like "serr.Err", hold what the error is made of.
+		if root := getSSAAddrRoot(v.X); typeIsError(root.Type()) && t.isChecked(root) {
+			return true, false
+		}
+
+		if stored, ok := getLocalStores(v); ok {
+			if fine, wrong := t.traceAll(stored); fine || wrong {
+				return fine, wrong
+			}
+		}
+
+		// Local variables that only hold nil, or whose stores cannot all be found,
+		// are judged like errors created where they are declared, and others,
+		// like the elements of a slice passed to the function, where they are loaded.
+		if root := getSSAAddrRoot(v.X); isSSAInstruction(root) {
+			return t.traceLeaf(root)
+		}
+	case *ssa.Extract:
+		switch tuple := v.Tuple.(type) {
+		case *ssa.Call:
+			return t.traceCall(tuple, v)
+		case *ssa.TypeAssert:
+			if typeIsError(tuple.X.Type()) {
+				return t.trace(tuple.X)
+			}
+		}
+	case *ssa.Call:
+		return t.traceCall(v, v)
+	}
+
+	return t.traceLeaf(v)
+}
+
+func (t ssaErrTracer) isChecked(v ssa.Value) bool {
+	for _, checked := range t.checked {
+		if sameSSAValue(v, checked) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t ssaErrTracer) traceAll(values []ssa.Value) (bool, bool) {
+	var wrong bool
+
+	for _, v := range values {
+		fineV, wrongV := t.trace(v)
+		if fineV {
+			return true, false
+		}
+		wrong = wrong || wrongV
+	}
+
+	return false, wrong
+}
+
+// traceCall follows the error returned by the call to the errors passed to it.
+// A call that is not passed any errors, or only nil ones, creates a fresh one.
+// Methods without arguments whose results are checked, like "rows.Err()",
+// return the error their receivers hold.
+func (t ssaErrTracer) traceCall(call *ssa.Call, result ssa.Value) (bool, bool) {
+	for _, condCall := range t.condCalls {
+		if sameSSAMethodCall(call.Common(), condCall.Common()) {
+			return t.trace(callReceiver(call.Common()))
+		}
+	}
+
+	fine, wrong := t.traceAll(getSSAErrArgs(t.pass, call.Common()))
+	if !fine && !wrong {
+		return t.traceLeaf(result)
+	}
+
+	return fine, wrong
+}
+
+// traceLeaf reports an error that does not come from other errors as fine
+// if it is created within the innermost check, and as wrong otherwise,
+// including when it is created by the enclosing function of a closure.
+func (t ssaErrTracer) traceLeaf(v ssa.Value) (bool, bool) {
+	instr, ok := v.(ssa.Instruction)
+	if !ok || instr.Block() == nil {
+		return true, false
+	}
+
+	if instr.Parent() == t.region.Parent() && t.region.Dominates(instr.Block()) {
+		return true, false
+	}
+
+	return false, true
+}
+
+// getSSACondCalls returns the calls of methods without arguments appearing
+// in the conditions of the function, like "rows.Err()" in "rows.Err() != nil".
+func getSSACondCalls(fn *ssa.Function) []*ssa.Call {
+	var (
+		calls []*ssa.Call
+		add   func(v ssa.Value)
+	)
+
+	add = func(v ssa.Value) {
+		switch v := unwrapSSAInterface(v).(type) {
+		case *ssa.UnOp:
+			if v.Op == token.NOT {
+				add(v.X)
+			}
+		case *ssa.BinOp:
+			add(v.X)
+			add(v.Y)
+		case *ssa.Call:
+			if callReceiver(v.Common()) != nil && len(callArgs(v.Common())) == 0 {
+				calls = append(calls, v)
+			}
+
+			for _, arg := range v.Call.Args {
+				add(arg)
+			}
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		if ifInstr, ok := block.Instrs[len(block.Instrs)-1].(*ssa.If); ok {
+			add(ifInstr.Cond)
+		}
+	}
+
+	return calls
+}
+
+// getLocalStores returns the values the load may read from the local variable,
+// or a field of it, including the values stored by closures capturing it.
+// False is returned if the variable may be assigned otherwise, e.g. through
+// a pointer passed to a function.
+func getLocalStores(load *ssa.UnOp) ([]ssa.Value, bool) {
+	addr := load.X
+
+	var fields []int
+	for {
+		fieldAddr, ok := addr.(*ssa.FieldAddr)
+		if !ok {
+			break
+		}
+
+		fields = append([]int{fieldAddr.Field}, fields...)
+		addr = fieldAddr.X
+	}
+
+	// Closures refer to the variables they capture through free variables,
+	// bound to the variables when the closures are created.
+	for {
+		freeVar, ok := addr.(*ssa.FreeVar)
+		if !ok {
+			break
+		}
+
+		if addr = getFreeVarBinding(freeVar); addr == nil {
+			return nil, false
+		}
+	}
+
+	if _, ok := addr.(*ssa.Alloc); !ok {
+		return nil, false
+	}
+
+	return getStoresTo(addr, fields, load.Parent())
+}
+
+// getStoresTo returns the values stored to the given fields of the variable
+// at the address, if it does not escape, as read by the function.
+func getStoresTo(addr ssa.Value, fields []int, fn *ssa.Function) ([]ssa.Value, bool) {
+	var stored []ssa.Value
+
+	for _, ref := range *addr.Referrers() {
+		switch r := ref.(type) {
+		case *ssa.Store:
+			if r.Addr != addr || len(fields) > 0 {
+				return nil, false
+			}
+			stored = append(stored, r.Val)
+
+		case *ssa.UnOp:
+			if r.Op != token.MUL {
+				return nil, false
+			}
+
+		case *ssa.FieldAddr:
+			if len(fields) == 0 || r.Field != fields[0] {
+				continue
+			}
+
+			fieldStored, ok := getStoresTo(r, fields[1:], fn)
+			if !ok {
+				return nil, false
+			}
+			stored = append(stored, fieldStored...)
+
+		case *ssa.MakeClosure:
+			closure, _ := r.Fn.(*ssa.Function)
+			if closure == nil {
+				return nil, false
+			}
+
+			for i, binding := range r.Bindings {
+				if binding != addr {
+					continue
+				}
+
+				// A closure deferred by the function may assign the variable
+				// after its return statements, like an error from outside of it.
+				if r.Parent() == fn && isDeferredClosure(r) {
+					stored = append(stored, closure.FreeVars[i])
+					continue
+				}
+
+				closureStored, ok := getStoresTo(closure.FreeVars[i], fields, fn)
+				if !ok {
+					return nil, false
+				}
+				stored = append(stored, closureStored...)
+			}
+
+		case *ssa.DebugRef:
+		default:
+			return nil, false
+		}
+	}
+
+	return stored, true
+}
+
+// isDeferredClosure reports whether the closure is called by a defer statement.
+func isDeferredClosure(makeClosure *ssa.MakeClosure) bool {
+	for _, ref := range *makeClosure.Referrers() {
+		if deferInstr, ok := ref.(*ssa.Defer); ok && deferInstr.Call.Value == makeClosure {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSSAAddrRoot returns the variable the address points into,
+// looking through fields, elements and free variables of closures.
+func getSSAAddrRoot(addr ssa.Value) ssa.Value {
+	for {
+		switch a := addr.(type) {
+		case *ssa.FieldAddr:
+			addr = a.X
+		case *ssa.IndexAddr:
+			addr = a.X
+		case *ssa.FreeVar:
+			binding := getFreeVarBinding(a)
+			if binding == nil {
+				return addr
+			}
+			addr = binding
+		default:
+			return addr
+		}
+	}
+}
+
+func isSSAInstruction(v ssa.Value) bool {
+	_, ok := v.(ssa.Instruction)
+	return ok
+}
+
+// getFreeVarBinding returns the variable the free variable of a closure
+// is bound to, or nil if the closure is not created exactly once.
+func getFreeVarBinding(freeVar *ssa.FreeVar) ssa.Value {
+	closure := freeVar.Parent()
+	if closure.Parent() == nil {
+		return nil
+	}
+
+	index := slices.Index(closure.FreeVars, freeVar)
+
+	var binding ssa.Value
+	for _, block := range closure.Parent().Blocks {
+		for _, instr := range block.Instrs {
+			makeClosure, _ := instr.(*ssa.MakeClosure)
+			if makeClosure == nil || makeClosure.Fn != closure {
+				continue
+			}
+
+			if binding != nil {
+				return nil
+			}
+			binding = makeClosure.Bindings[index]
+		}
+	}
+
+	return binding
+}
+
+// sameSSAValue reports whether the values hold the same error: loads of
+// the same variable or field, or calls of the same method without arguments
+// on the same receiver, like "rows.Err()".
+func sameSSAValue(x, y ssa.Value) bool {
+	if x == y {
+		return true
+	}
+
+	switch x := x.(type) {
+	case *ssa.UnOp:
+		y, ok := y.(*ssa.UnOp)
+		return ok && x.Op == token.MUL && y.Op == token.MUL && sameSSAValue(x.X, y.X)
+	case *ssa.FieldAddr:
+		y, ok := y.(*ssa.FieldAddr)
+		return ok && x.Field == y.Field && sameSSAValue(x.X, y.X)
+	case *ssa.Field:
+		y, ok := y.(*ssa.Field)
+		return ok && x.Field == y.Field && sameSSAValue(x.X, y.X)
+	case *ssa.FreeVar:
+		y, ok := y.(*ssa.FreeVar)
+		return ok && x.Parent() == y.Parent() && sameSSAValue(getFreeVarBinding(x), getFreeVarBinding(y))
+	case *ssa.Call:
+		y, ok := y.(*ssa.Call)
+		return ok && sameSSAMethodCall(x.Common(), y.Common())
+	}
+
+	return false
+}
+
+func sameSSAMethodCall(x, y *ssa.CallCommon) bool {
+	if len(callArgs(x)) != 0 || len(callArgs(y)) != 0 {
+		return false
+	}
+
+	xRecv, yRecv := callReceiver(x), callReceiver(y)
+	if xRecv == nil || yRecv == nil || !sameSSAValue(xRecv, yRecv) {
+		return false
+	}
+
+	if x.IsInvoke() {
+		return y.IsInvoke() && x.Method == y.Method
+	}
+
+	return x.StaticCallee() != nil && x.StaticCallee() == y.StaticCallee()
+}
+
+// getSSAErrArgs returns the errors passed to a call that may flow into its result,
+// following the same rules as getErrArgs.
+func getSSAErrArgs(pass *analysis.Pass, common *ssa.CallCommon) []ssa.Value {
+	if builtin, ok := common.Value.(*ssa.Builtin); ok {
+		if builtin.Name() != "append" || len(common.Args) != 2 {
+			return nil
+		}
+
+		return append([]ssa.Value{common.Args[0]}, expandVarargs(common.Args[1])...)
+	}
+
+	args := callArgs(common)
+
+	// Methods of errors without arguments, like "merr.ErrorOrNil()", return
+	// the error the receiver holds, and "status.Convert(err).Err()" returns
+	// the error the receiver is made of. Other methods, like "tx.Rollback()",
+	// create fresh errors, unless their results are checked, see traceCall.
+	if recv := callReceiver(common); recv != nil && len(args) == 0 {
+		if typeIsError(recv.Type()) || isSSAModeledCall(recv) {
+			return []ssa.Value{recv}
+		}
+
+		return nil
+	}
+
+	fn := staticCalleeObject(common)
+
+	var model errFuncModel
+	hasModel := false
+	if fn != nil {
+		model, hasModel = getSSAErrFuncModel(fn)
+	}
+
+	if fn != nil && !hasModel {
+		if wrapped, ok := getSSAErrorfErrArgs(pass, fn, args); ok {
+			return wrapped
+		}
+	}
+
+	var (
+		fact    errFlowFact
+		hasFact bool
+	)
+	if fn != nil {
+		hasFact = pass.ImportObjectFact(fn.Origin(), &fact)
+	}
+
+	var errArgs []ssa.Value
+
+	for i, arg := range args {
+		if hasModel && !model.isCause(fn, i) {
+			continue
+		}
+
+		if !hasModel && hasFact && fact.paramFlow(i) == errFlowDropped {
+			continue
+		}
+
+		values := []ssa.Value{arg}
+		if common.Signature().Variadic() && i == len(args)-1 {
+			values = expandVarargs(arg)
+		}
+
+		for _, v := range values {
+			if v = unwrapSSAInterface(v); typeIsError(v.Type()) || typeIsErrorSlice(v.Type()) {
+				errArgs = append(errArgs, v)
+			}
+		}
+	}
+
+	return errArgs
+}
+
+// isSSAModeledCall reports whether the value is returned by a call to a modeled
+// function deriving its result from errors, see getModeledReceiverCall.
+func isSSAModeledCall(v ssa.Value) bool {
+	call, _ := v.(*ssa.Call)
+	if call == nil {
+		return false
+	}
+
+	fn := staticCalleeObject(call.Common())
+	if fn == nil {
+		return false
+	}
+
+	model, ok := getSSAErrFuncModel(fn)
+
+	return ok && len(model.causes) > 0
+}
+
+// getSSAErrorfErrArgs returns the errors wrapped with %w by an Errorf-like call,
+// see getErrorfErrArgs.
+func getSSAErrorfErrArgs(pass *analysis.Pass, fn *types.Func, args []ssa.Value) ([]ssa.Value, bool) {
+	if fn.FullName() != "fmt.Errorf" {
+		result, _ := pass.ResultOf[printf.Analyzer].(*printf.Result)
+		if result == nil || result.Kind(fn) != printf.KindErrorf {
+			return nil, false
+		}
+	}
+
+	sig := fn.Signature()
+	if !sig.Variadic() || sig.Params().Len() < 2 || len(args) != sig.Params().Len() {
+		return nil, false
+	}
+
+	format, _ := args[len(args)-2].(*ssa.Const)
+	if format == nil || format.Value == nil || format.Value.Kind() != constant.String {
+		return nil, false
+	}
+
+	operands := expandVarargs(args[len(args)-1])
+
+	var errArgs []ssa.Value
+	for _, v := range parseFormatVerbs(constant.StringVal(format.Value)) {
+		if v.verb != 'w' || v.operand >= len(operands) || operands[v.operand] == nil {
+			continue
+		}
+
+		if operand := unwrapSSAInterface(operands[v.operand]); typeIsError(operand.Type()) || typeIsErrorSlice(operand.Type()) {
+			errArgs = append(errArgs, operand)
+		}
+	}
+
+	return errArgs, len(errArgs) > 0
+}
+
+// expandVarargs returns the values packed into the slice passed to a variadic
+// parameter, or the slice itself if it was not made for the call, like "errs..."
+// Elements that are not found are nil.
+func expandVarargs(slice ssa.Value) []ssa.Value {
+	if isNilConst(slice) {
+		return nil
+	}
+
+	s, _ := slice.(*ssa.Slice)
+	if s == nil {
+		return []ssa.Value{slice}
+	}
+
+	array, _ := s.X.(*ssa.Alloc)
+	if array == nil {
+		return []ssa.Value{slice}
+	}
+
+	arrayType, _ := types.Unalias(array.Type()).(*types.Pointer).Elem().Underlying().(*types.Array)
+	if arrayType == nil {
+		return []ssa.Value{slice}
+	}
+
+	values := make([]ssa.Value, arrayType.Len())
+	for _, ref := range *array.Referrers() {
+		indexAddr, _ := ref.(*ssa.IndexAddr)
+		if indexAddr == nil {
+			continue
+		}
+
+		index, _ := indexAddr.Index.(*ssa.Const)
+		if index == nil {
+			continue
+		}
+
+		i := index.Int64()
+		if i < 0 || i >= int64(len(values)) {
+			continue
+		}
+
+		for _, indexRef := range *indexAddr.Referrers() {
+			if store, ok := indexRef.(*ssa.Store); ok && store.Addr == indexAddr {
+				values[i] = store.Val
+			}
+		}
+	}
+
+	return values
+}
+
+// unwrapSSAInterface returns the value converted to an interface like any.
+func unwrapSSAInterface(v ssa.Value) ssa.Value {
+	switch x := v.(type) {
+	case *ssa.MakeInterface:
+		return x.X
+	case *ssa.ChangeInterface:
+		return x.X
+	}
+
+	return v
+}
+
+// callArgs returns the arguments of a call except for the receiver of a method.
+func callArgs(common *ssa.CallCommon) []ssa.Value {
+	if !common.IsInvoke() && common.Signature().Recv() != nil && len(common.Args) > 0 {
+		return common.Args[1:]
+	}
+
+	return common.Args
+}
+
+// callReceiver returns the receiver of a method call, or nil if the call is not one.
+func callReceiver(common *ssa.CallCommon) ssa.Value {
+	if common.IsInvoke() {
+		return common.Value
+	}
+
+	if common.Signature().Recv() != nil && len(common.Args) > 0 {
+		return common.Args[0]
+	}
+
+	return nil
+}
+
+// staticCalleeObject returns the function called, if it is known statically.
+func staticCalleeObject(common *ssa.CallCommon) *types.Func {
+	callee := common.StaticCallee()
+	if callee == nil {
+		return nil
+	}
+
+	fn, _ := callee.Object().(*types.Func)
+
+	return fn
+}
+
+// getSSAErrFuncModel returns the model of the function, see getErrFuncModel.
+func getSSAErrFuncModel(fn *types.Func) (errFuncModel, bool) {
+	if fn.Pkg() == nil || fn.Signature().Recv() != nil {
+		return errFuncModel{}, false
+	}
+
+	model, ok := errFuncModels[fn.Pkg().Path()][fn.Name()]
+
+	return model, ok
+}
+
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+
+	return ok && c.IsNil()
+}
@@ -0,0 +1,14 @@
+package checkers
+
+import (
+	"context"
+	"errors"
+)
+
+func IsRetryable(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+func Succeeded(err error) bool {
+	return err == nil
+}
@@ -0,0 +1,85 @@
+package fixes
+
+import (
+	"errors"
+	"fmt"
+)
+
+func ReturningWrongError() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err != nil {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func WrappingWrongError() (int, error) {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err != nil {
+		return 0, fmt.Errorf("wrapped: %w", anotherErr) // want "checked `err` but wrapped `anotherErr` in fmt\\.Errorf"
+	}
+
+	return 1, nil
+}
+
+func InvertedCheck() error {
+	txErr := errors.New("tx error")
+	err := errors.New("error")
+
+	if txErr == nil {
+		return nil
+	}
+
+	return fmt.Errorf("tx: %w", err) // want "checked `txErr` but wrapped `err` in fmt\\.Errorf"
+}
+
+func SeveralCheckedErrors() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	anotherErr := errors.New("another")
+
+	if errA != nil || errB != nil {
+		return anotherErr // want "checked `errA` or `errB` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func SeveralWrongErrors() error {
+	err := errors.New("error")
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", errA, errB) // want "checked `err` but wrapped `errA`, `errB` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func CheckedErrorIsShadowed() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err != nil {
+		shadowing := func() error {
+			err := 1
+			_ = err
+
+			if true {
+				return anotherErr // want "checked `err` but returned `anotherErr`"
+			}
+
+			return nil
+		}
+
+		return shadowing()
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+func New(msg string) error {
+	return stderrors.New(msg)
+}
+
+func Wrap(err error, msg string) error {
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func WithHint(err error, msg string) error {
+	return err
+}
+
+func Mark(err error, reference error) error {
+	return err
+}
+
+func CombineErrors(err, otherErr error) error {
+	return stderrors.Join(err, otherErr)
+}
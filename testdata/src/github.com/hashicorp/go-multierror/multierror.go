@@ -0,0 +1,35 @@
+package multierror
+
+import "strings"
+
+type Error struct {
+	Errors []error
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e *Error) ErrorOrNil() error {
+	if e == nil || len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func Append(err error, errs ...error) *Error {
+	merr, _ := err.(*Error)
+	if merr == nil {
+		merr = &Error{}
+	}
+
+	merr.Errors = append(merr.Errors, errs...)
+
+	return merr
+}
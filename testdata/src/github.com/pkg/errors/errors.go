@@ -0,0 +1,51 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+type withMessage struct {
+	cause error
+	msg   string
+}
+
+func (w *withMessage) Error() string { return w.msg + ": " + w.cause.Error() }
+
+func (w *withMessage) Unwrap() error { return w.cause }
+
+func New(message string) error {
+	return stderrors.New(message)
+}
+
+func Errorf(format string, args ...any) error {
+	return stderrors.New(fmt.Sprintf(format, args...))
+}
+
+func Wrap(err error, message string) error {
+	return &withMessage{cause: err, msg: message}
+}
+
+func Wrapf(err error, format string, args ...any) error {
+	return &withMessage{cause: err, msg: fmt.Sprintf(format, args...)}
+}
+
+func WithMessage(err error, message string) error {
+	return &withMessage{cause: err, msg: message}
+}
+
+func WithStack(err error) error {
+	return err
+}
+
+func Cause(err error) error {
+	return err
+}
+
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+func As(err error, target any) bool {
+	return stderrors.As(err, target)
+}
@@ -0,0 +1,11 @@
+package multierr
+
+import "errors"
+
+func Append(left, right error) error {
+	return errors.Join(left, right)
+}
+
+func Combine(errs ...error) error {
+	return errors.Join(errs...)
+}
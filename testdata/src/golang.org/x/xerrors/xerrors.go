@@ -0,0 +1,11 @@
+package xerrors
+
+import "errors"
+
+func New(text string) error {
+	return errors.New(text)
+}
+
+func Opaque(err error) error {
+	return errors.New(err.Error())
+}
@@ -0,0 +1,32 @@
+package status
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+)
+
+type Status struct {
+	code codes.Code
+	msg  string
+}
+
+func (s *Status) Err() error {
+	if s.code == codes.OK {
+		return nil
+	}
+
+	return errors.New(s.msg)
+}
+
+func New(c codes.Code, msg string) *Status {
+	return &Status{code: c, msg: msg}
+}
+
+func Error(c codes.Code, msg string) error {
+	return New(c, msg).Err()
+}
+
+func Convert(err error) *Status {
+	return New(codes.Internal, err.Error())
+}
@@ -1,8 +1,19 @@
 package pkg
 
 import (
+	"checkers"
+	"context"
 	"errors"
 	"fmt"
+	"wrappers"
+
+	crdberrors "github.com/cockroachdb/errors"
+	multierror "github.com/hashicorp/go-multierror"
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var ExternalError = errors.New("external error")
@@ -21,7 +32,7 @@ func CheckingAndReturningDifferentErrors() error {
 	var err2 = errors.New("2")
 
 	if err1 != nil {
-		return err2 // want "returning not the error that was checked"
+		return err2 // want "checked `err1` but returned `err2`"
 	}
 
 	return nil
@@ -32,7 +43,7 @@ func CheckingAndReturningDifferentErrorsNoLintNoCorrecterr() error {
 	var err2 = errors.New("2")
 
 	if err1 != nil {
-		return err2 //nolint:foo,bar // want "returning not the error that was checked"
+		return err2 //nolint:foo,bar // want "checked `err1` but returned `err2`"
 	}
 
 	return nil
@@ -41,7 +52,7 @@ func CheckingAndReturningDifferentErrorsNoLintNoCorrecterr() error {
 func CheckingAndReturningDifferentErrors2() error {
 	var err1 = errors.New("1")
 	if err2 := errors.New("2"); err2 != nil {
-		return err1 // want "returning not the error that was checked"
+		return err1 // want "checked `err2` but returned `err1`"
 	}
 
 	return nil
@@ -52,7 +63,7 @@ func ErrorfWrap() error {
 	err2 := errors.New("2")
 
 	if err1 != nil {
-		return fmt.Errorf("error: %w", err2) // want "returning not the error that was checked"
+		return fmt.Errorf("error: %w", err2) // want "checked `err1` but wrapped `err2` in fmt\\.Errorf"
 	}
 
 	return nil
@@ -63,7 +74,7 @@ func FuncLit() {
 
 	func() error {
 		if innerErr := errors.New("inner"); innerErr != nil {
-			return err // want "returning not the error that was checked"
+			return err // want "checked `innerErr` but returned `err`"
 		}
 
 		return nil
@@ -75,7 +86,7 @@ func AssignFuncLit() error {
 
 	funcLitErr := func() error {
 		if innerErr := errors.New("inner"); innerErr != nil {
-			return err // want "returning not the error that was checked"
+			return err // want "checked `innerErr` but returned `err`"
 		}
 
 		return nil
@@ -91,7 +102,7 @@ func Switch() error {
 	case false:
 	case true:
 		if innerErr := errors.New("inner"); innerErr != nil {
-			return err // want "returning not the error that was checked"
+			return err // want "checked `innerErr` but returned `err`"
 		}
 	}
 
@@ -103,7 +114,7 @@ func RangeStmt() error {
 
 	for range 5 {
 		if innerErr := errors.New("inner"); innerErr != nil {
-			return err // want "returning not the error that was checked"
+			return err // want "checked `innerErr` but returned `err`"
 		}
 	}
 
@@ -117,7 +128,7 @@ func ForStmt() error {
 		_ = i
 
 		if innerErr := errors.New("inner"); innerErr != nil {
-			return err // want "returning not the error that was checked"
+			return err // want "checked `innerErr` but returned `err`"
 		}
 	}
 
@@ -130,7 +141,7 @@ func NestedIfStatements() error {
 
 	if true {
 		if err != nil {
-			return anotherErr // want "returning not the error that was checked"
+			return anotherErr // want "checked `err` but returned `anotherErr`"
 		}
 	}
 
@@ -142,7 +153,7 @@ func TripleFooWrapOfWrongError() error {
 	anotherError := errors.New("another")
 
 	if err != nil {
-		return fooWrap(1, fooWrap(2, fooWrap(3, anotherError, "c"), "b"), "a") // want "returning not the error that was checked"
+		return fooWrap(1, fooWrap(2, fooWrap(3, anotherError, "c"), "b"), "a") // want "checked `err` but wrapped `anotherError` in fooWrap"
 	}
 
 	return nil
@@ -153,7 +164,7 @@ func ReturningMessage() (error, string) {
 	anotherErr := errors.New("another error")
 
 	if err != nil {
-		return anotherErr, err.Error() // want "returning not the error that was checked"
+		return anotherErr, err.Error() // want "checked `err` but returned its message only via `\\.Error\\(\\)`"
 	}
 
 	return nil, "foo"
@@ -167,7 +178,7 @@ func ClosureErrors() error {
 		}
 
 		if _, innerErr := doSmth(); innerErr != nil {
-			return fooWrap(1, err, "a") // want "returning not the error that was checked"
+			return fooWrap(1, err, "a") // want "checked `innerErr` but wrapped `err` in fooWrap"
 		}
 
 		return nil
@@ -182,7 +193,7 @@ func ClosureReturnsErrorAssignedOutside() error {
 
 	err := closureWrapper(func() error {
 		if funcErr != nil {
-			return anotherFuncErr // want "returning not the error that was checked"
+			return anotherFuncErr // want "checked `funcErr` but returned `anotherFuncErr`"
 		}
 
 		return nil
@@ -197,7 +208,7 @@ func ClosureReturnsErrorAssignedInside() error {
 		anotherInnerErr := errors.New("another inner")
 
 		if innerErr != nil {
-			return anotherInnerErr // want "returning not the error that was checked"
+			return anotherInnerErr // want "checked `innerErr` but returned `anotherInnerErr`"
 		}
 
 		return nil
@@ -214,7 +225,7 @@ func ClosureReturnsErrorDeclaredOutside() error {
 
 	err := closureWrapper(func() error {
 		if innerErr != nil {
-			return anotherInnerErr // want "returning not the error that was checked"
+			return anotherInnerErr // want "checked `innerErr` but returned `anotherInnerErr`"
 		}
 
 		return nil
@@ -231,7 +242,7 @@ func ClosureReturnsErrorDeclaredInside() error {
 		)
 
 		if innerErr != nil {
-			return anotherInnerErr // want "returning not the error that was checked"
+			return anotherInnerErr // want "checked `innerErr` but returned `anotherInnerErr`"
 		}
 
 		return nil
@@ -246,7 +257,7 @@ func ClosureInDeclaration() error {
 		anotherInnerErr := errors.New("another")
 
 		if innerErr != nil {
-			return anotherInnerErr // want "returning not the error that was checked"
+			return anotherInnerErr // want "checked `innerErr` but returned `anotherInnerErr`"
 		}
 
 		return nil
@@ -261,484 +272,2243 @@ func NoInitialLocalErrNames() {
 		anotherInnerErr := errors.New("another")
 
 		if innerErr != nil {
-			return anotherInnerErr // want "returning not the error that was checked"
+			return anotherInnerErr // want "checked `innerErr` but returned `anotherInnerErr`"
 		}
 
 		return nil
 	})
 }
 
+func ShadowedErrReturnedAfterCheck() error {
+	err := errors.New("outer")
+	if err != nil {
+		err := errors.New("inner")
+		if true {
+			return err // want "checked `err` but returned a different `err`"
+		}
+	}
+
+	return nil
+}
+
+func ElseIfChecksAnotherError() error {
+	err := errors.New("error")
+	txErr := errors.New("tx error")
+
+	if err != nil {
+		return err
+	} else if txErr != nil {
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func ElseBlockWithNestedCheck() error {
+	err := errors.New("error")
+	txErr := errors.New("tx error")
+	anotherErr := errors.New("another")
+
+	if err != nil {
+		return err
+	} else {
+		if txErr != nil {
+			return anotherErr // want "checked `txErr` but returned `anotherErr`"
+		}
+	}
+
+	return nil
+}
+
+func ElseOfNestedIfInsideCheck() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err != nil {
+		if len(err.Error()) > 0 {
+			return err
+		} else {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+	}
+
+	return nil
+}
+
+func InvertedEarlyReturn() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err == nil {
+		return nil
+	}
+
+	return anotherErr // want "checked `err` but returned `anotherErr`"
+}
+
+func EqualNilWithElse() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err == nil {
+		fmt.Println("ok")
+	} else {
+		return fmt.Errorf("fallback: %w", anotherErr) // want "checked `err` but wrapped `anotherErr` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ReturningErrKnownToBeNil() error {
+	err := errors.New("error")
+
+	if err == nil {
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func ReturningErrKnownToBeNilInElse() (int, error) {
+	_, err := doSmth()
+
+	if err != nil {
+		return 0, fmt.Errorf("wrapped: %w", err)
+	} else {
+		return 1, err // want "returned `err` which is known to be nil here"
+	}
+}
+
+func ReversedNilCheck() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if nil != err {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func ParenthesizedNilCheck() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if (err != nil) && len(anotherErr.Error()) > 0 {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func NilCheckAndFlag(retryable bool) error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err != nil && retryable {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func EitherErrorChecked() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	anotherErr := errors.New("another")
+
+	if errA != nil || errB != nil {
+		return anotherErr // want "checked `errA` or `errB` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func BothNilElse() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	anotherErr := errors.New("another")
+
+	if errA == nil && errB == nil {
+		return nil
+	} else {
+		return anotherErr // want "checked `errA` or `errB` but returned `anotherErr`"
+	}
+}
+
+func NegatedNilCheck() error {
+	err := errors.New("error")
+
+	if !(err != nil) {
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func ErrorsIsWrong() error {
+	err := errors.New("original")
+	anotherErr := errors.New("another")
+
+	wrappedErr := fmt.Errorf("wrapped: %w", err)
+	if errors.Is(wrappedErr, err) {
+		return anotherErr // want "checked `wrappedErr` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func ErrorsIsNegatedWrong() error {
+	err := errors.New("original")
+	anotherErr := errors.New("another")
+
+	if !errors.Is(err, ExternalError) {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func ErrorsAsWrong() error {
+	err := errors.New("original")
+	anotherErr := errors.New("another")
+
+	var target interface{ Timeout() bool }
+	if err != nil && errors.As(err, &target) {
+		return fmt.Errorf("timeout: %w", anotherErr) // want "checked `err` or `target` but wrapped `anotherErr` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func FooCheckWrong() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if fooCheck(1, err, "a") {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func FooCheckWrappedWrong() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if fooCheck(1, err, "a") {
+		return fmt.Errorf("error: %w", anotherErr) // want "checked `err` but wrapped `anotherErr` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func RetryableCheckWrong() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if checkers.IsRetryable(err) {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func NotFoundCheckWrong() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if !isNotFound(err) {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func CheckedErrReplacedByWrongErr() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil {
+		return replaceErr(err, anotherErr) // want "checked `err` but wrapped `anotherErr` in replaceErr"
+	}
+
+	return nil
+}
+
+func ReturningErrDroppedBeforeCheck() error {
+	err := errors.New("some error")
+	dropped := wrappers.Drop(err)
+
+	if err != nil {
+		return dropped // want "checked `err` but returned `dropped`"
+	}
+
+	return nil
+}
+
+func SelectCase(errCh chan error) error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	select {
+	case <-errCh:
+		if err != nil {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+	default:
+	}
+
+	return nil
+}
+
+func TypeSwitchClause(v any) error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch v.(type) {
+	case string:
+		if err != nil {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+	}
+
+	return nil
+}
+
+func BareBlock() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	{
+		if err != nil {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+	}
+
+	return nil
+}
+
+func LabeledLoop() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+outer:
+	for i := range 3 {
+		for range i {
+			if err != nil {
+				return anotherErr // want "checked `err` but returned `anotherErr`"
+			}
+
+			continue outer
+		}
+	}
+
+	return nil
+}
+
+func GoClosure() {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	go func() error {
+		if err != nil {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+
+		return nil
+	}()
+}
+
+func DeferClosure() {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	defer func() error {
+		if err != nil {
+			return anotherErr // want "checked `err` but returned `anotherErr`"
+		}
+
+		return nil
+	}()
+}
+
+func ReturnedClosure() func() error {
+	err := errors.New("error")
+
+	return func() error {
+		if innerErr := errors.New("inner"); innerErr != nil {
+			return err // want "checked `innerErr` but returned `err`"
+		}
+
+		return nil
+	}
+}
+
+func ClosureInCompositeLiteral() []func() error {
+	err := errors.New("error")
+
+	return []func() error{
+		func() error {
+			if innerErr := errors.New("inner"); innerErr != nil {
+				return err // want "checked `innerErr` but returned `err`"
+			}
+
+			return nil
+		},
+	}
+}
+
+func ClosureSentToChannel(fnCh chan func() error) {
+	err := errors.New("error")
+
+	fnCh <- func() error {
+		if innerErr := errors.New("inner"); innerErr != nil {
+			return err // want "checked `innerErr` but returned `err`"
+		}
+
+		return nil
+	}
+}
+
+func SwitchCaseNilCheck() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch {
+	case err != nil:
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func SwitchTagDefault() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch err {
+	case nil:
+		return nil
+	default:
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+}
+
+func SwitchTagNilCase() error {
+	err := errors.New("error")
+
+	switch err {
+	case nil:
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func SwitchTagSentinelCase() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch err {
+	case ExternalError:
+		return anotherErr // want "checked `ExternalError` or `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func SwitchCaseErrorsIs() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch {
+	case errors.Is(err, ExternalError):
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func SwitchLaterCase(retryable bool) error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	switch {
+	case err == nil:
+		return nil
+	case retryable:
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func IfInitWrapsWrongError() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if wrapped := fmt.Errorf("wrapped: %w", anotherErr); err != nil {
+		return wrapped // want "checked `err` but returned `wrapped`"
+	}
+
+	return nil
+}
+
+func ForInitDeclaredErr() error {
+	err := errors.New("error")
+
+	for _, iterErr := doSmth(); iterErr == nil; {
+		if err != nil {
+			return iterErr // want "checked `err` but returned `iterErr`"
+		}
+	}
+
+	return nil
+}
+
+func RangeValueErr(errs []error) error {
+	err := errors.New("error")
+
+	for _, rangeErr := range errs {
+		if err != nil {
+			return rangeErr // want "checked `err` but returned `rangeErr`"
+		}
+	}
+
+	return nil
+}
+
+func SwappedCustomErrors() error {
+	appErr := newAppError()
+	anotherAppErr := newAppError()
+
+	if appErr != nil {
+		return anotherAppErr // want "checked `appErr` but returned `anotherAppErr`"
+	}
+
+	return nil
+}
+
+func SwappedInterfaceErrors() error {
+	codedErr := newCodedError()
+	anotherCodedErr := newCodedError()
+
+	if codedErr != nil {
+		return fmt.Errorf("coded: %w", anotherCodedErr) // want "checked `codedErr` but wrapped `anotherCodedErr` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func SwappedTypeParamErrors[E error](newErr func() E) error {
+	err := newErr()
+	anotherErr := newErr()
+
+	if errors.Is(err, ExternalError) {
+		return anotherErr // want "checked `err` but returned `anotherErr`"
+	}
+
+	return nil
+}
+
+func SwappedFieldErrors() error {
+	req, res := newResult(), newResult()
+
+	if res.Err != nil {
+		return req.Err // want "checked `res.Err` but returned `req.Err`"
+	}
+
+	return nil
+}
+
+func SwappedNestedFieldErrors() error {
+	var resp response
+
+	if resp.Body.Err != nil {
+		return fmt.Errorf("body: %w", resp.Header.Err) // want "checked `resp.Body.Err` but wrapped `resp.Header.Err` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func SwappedParamFieldErrors(req, res result) error {
+	if res.Err != nil {
+		return req.Err // want "checked `res.Err` but returned `req.Err`"
+	}
+
+	return nil
+}
+
+func SwappedDereferencedFieldErrors() error {
+	req, res := &result{}, &result{}
+
+	if (*res).Err != nil {
+		return (*req).Err // want "checked `res.Err` but returned `\\(\\*req\\)\\.Err`"
+	}
+
+	return nil
+}
+
+func FieldCheckedLocalReturned() error {
+	res := newResult()
+	err := errors.New("local")
+
+	if res.Err != nil {
+		return err // want "checked `res.Err` but returned `err`"
+	}
+
+	return nil
+}
+
+func RowsErrCheckedLocalReturned() error {
+	rows := newRows()
+	err := errors.New("scan")
+
+	for rows.Next() {
+	}
+
+	if rows.Err() != nil {
+		return err // want "checked `rows.Err\\(\\)` but returned `err`"
+	}
+
+	return nil
+}
+
+func CtxErrCheckedLastErrReturned(ctx context.Context) error {
+	var lastErr error
+
+	for range 3 {
+		lastErr = errors.New("attempt failed")
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("retry: %w", lastErr) // want "checked `ctx.Err\\(\\)` but wrapped `lastErr` in fmt\\.Errorf"
+		}
+	}
+
+	return lastErr
+}
+
+func ErrCheckedRowsErrReturned() error {
+	rows := newRows()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	err := errors.New("scan")
+	if err != nil {
+		return rows.Err() // want "checked `err` but returned `rows.Err\\(\\)`"
+	}
+
+	return nil
+}
+
+func JoinedErrsWithoutCheckedErr() error {
+	var errs []error
+	errs = append(errs, errors.New("first"))
+
+	if err := errors.New("second"); err != nil {
+		return errors.Join(errs...) // want "checked `err` but wrapped `errs` in errors\\.Join"
+	}
+
+	return nil
+}
+
+func AppendedUnrelatedErr(items []string) error {
+	var errs []error
+	other := errors.New("other")
+
+	for range items {
+		if err := errors.New("item"); err != nil {
+			errs = append(errs, other) // want "checked `err` but appended `other` to `errs`"
+			continue
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func MultierrAppendedUnrelatedErr() error {
+	var merr error
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		merr = multierr.Append(merr, other) // want "checked `err` but appended `other` to `merr`"
+	}
+
+	return merr
+}
+
+func MultierrorAppendedUnrelatedErr() error {
+	var result *multierror.Error
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		result = multierror.Append(result, other) // want "checked `err` but appended `other` to `result`"
+	}
+
+	return result.ErrorOrNil()
+}
+
+func PkgErrorsWrapOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return pkgerrors.Wrap(other, "failed") // want "checked `err` but wrapped `other` in pkgerrors\\.Wrap"
+	}
+
+	return nil
+}
+
+func PkgErrorsWrapfOtherFormattingChecked() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return pkgerrors.Wrapf(other, "because of %v", err) // want "checked `err` but wrapped `other` in pkgerrors\\.Wrapf"
+	}
+
+	return nil
+}
+
+func PkgErrorsErrorfFormattingOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return pkgerrors.Errorf("failed after %v", other) // want "checked `err` but formatted `other` in pkgerrors\\.Errorf"
+	}
+
+	return nil
+}
+
+func PkgErrorsIsReturnOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if pkgerrors.Is(err, ExternalError) {
+		return other // want "checked `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func CockroachMarkOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return crdberrors.Mark(other, err) // want "checked `err` but wrapped `other` in crdberrors\\.Mark"
+	}
+
+	return nil
+}
+
+func GRPCStatusOfOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return status.Convert(other).Err() // want "checked `err` but wrapped `other` in status\\.Convert"
+	}
+
+	return nil
+}
+
+func ErrorfWrapsOtherFormatsChecked() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", other, err) // want "checked `err` but formatted it with %v while wrapping `other` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ErrorfIndexedVerbs() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return fmt.Errorf("%[2]w (%[1]s)", err, other) // want "checked `err` but formatted it with %s while wrapping `other` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ErrorfWrapsSeveralOthers() error {
+	err := errors.New("1")
+	other := errors.New("2")
+	another := errors.New("3")
+
+	if err != nil {
+		return fmt.Errorf("%w, %w", other, another) // want "checked `err` but wrapped `other`, `another` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ErrorfWrapperWrapsOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return errorfWrapper("%v: %w", err, other) // want "checked `err` but formatted it with %v while wrapping `other` in errorfWrapper"
+	}
+
+	return nil
+}
+
+func BareReturnOfStaleErr() (n int, err error) {
+	n, err = doSmth()
+
+	if txErr := errors.New("tx"); txErr != nil {
+		return // want "checked `txErr` but returned `err`"
+	}
+
+	return
+}
+
+func BareReturnOfErrAssignedInLoop(items []string) (err error) {
+	for range items {
+		if txErr := errors.New("tx"); txErr != nil {
+			return // want "checked `txErr` but returned `err`"
+		}
+
+		_, err = doSmth()
+	}
+
+	return nil
+}
+
+func BareReturnAfterAssigningOther() (retErr error) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		retErr = other // want "checked `err` but assigned `other` to `retErr`"
+		return
+	}
+
+	return nil
+}
+
+func ReturningStaleNamedResult() (n int, err error) {
+	n, err = doSmth()
+
+	if txErr := errors.New("tx"); txErr != nil {
+		return 0, err // want "checked `txErr` but returned `err`"
+	}
+
+	return n, nil
+}
+
+func AssigningOtherBeforeReturn() error {
+	err := errors.New("1")
+	other := errors.New("2")
+	var retErr error
+
+	if err != nil {
+		retErr = fmt.Errorf("failed: %w", other) // want "checked `err` but assigned `other` wrapped in fmt\\.Errorf to `retErr`"
+		return retErr
+	}
+
+	return nil
+}
+
+func AssigningOtherToField(items []string) []result {
+	results := make([]result, len(items))
+	other := errors.New("other")
+
+	for i := range items {
+		var res result
+
+		if err := errors.New("item"); err != nil {
+			res.Err = other // want "checked `err` but assigned `other` to `res.Err`"
+			results[i] = res
+			continue
+		}
+	}
+
+	return results
+}
+
+func AssigningLastErrToFirstErr(items []string) error {
+	var firstErr, lastErr error
+
+	for range items {
+		err := errors.New("item")
+		if err != nil {
+			firstErr = lastErr // want "checked `err` but assigned `lastErr` to `firstErr`"
+			break
+		}
+		_, lastErr = doSmth()
+	}
+
+	return firstErr
+}
+
+func AssigningOtherToCapturedVar() error {
+	var captured error
+	other := errors.New("other")
+
+	func() {
+		if err := errors.New("inner"); err != nil {
+			captured = other // want "checked `err` but assigned `other` to `captured`"
+		}
+	}()
+
+	return captured
+}
+
+func WrappingAfterReturn() error {
+	err := errors.New("1")
+	var wrapped error
+
+	if err != nil {
+		return wrapped // want "checked `err` but returned `wrapped`"
+	}
+
+	wrapped = fmt.Errorf("wrapped: %w", err)
+
+	return wrapped
+}
+
+func ReassigningWrapperBeforeCheck() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	wrapped = other
+
+	if err != nil {
+		return wrapped // want "checked `err` but returned `wrapped`"
+	}
+
+	return nil
+}
+
+func TypeAssertedOtherErr() error {
+	err := errors.New("error")
+	other := errors.New("other")
+
+	if err != nil {
+		if appErr, ok := other.(*AppError); ok {
+			return appErr // want "checked `err` but returned `appErr`"
+		}
+	}
+
+	return nil
+}
+
+func FormattingOtherErr() error {
+	err := errors.New("error")
+	other := errors.New("other")
+
+	if err != nil {
+		return fmt.Errorf("failed after %v", other) // want "checked `err` but formatted `other` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func FormattingOtherErrInErrorfWrapper() error {
+	err := errors.New("error")
+	other := errors.New("other")
+
+	if err != nil {
+		return errorfWrapper("failed after %s", other) // want "checked `err` but formatted `other` in errorfWrapper"
+	}
+
+	return nil
+}
+
 // ----------------------------------------------------
 // Suppressed triggers
 
-func CheckingAndReturningDifferentErrorsNoLint() error {
-	var err1 = errors.New("1")
-	var err2 = errors.New("2")
+func CheckingAndReturningDifferentErrorsNoLint() error {
+	var err1 = errors.New("1")
+	var err2 = errors.New("2")
+
+	if err1 != nil {
+		return err2 //nolint
+	}
+
+	return nil
+}
+
+func CheckingAndReturningDifferentErrorsNoLintAll() error {
+	var err1 = errors.New("1")
+	var err2 = errors.New("2")
+
+	if err1 != nil {
+		return err2 //nolint:all
+	}
+
+	return nil
+}
+
+func CheckingAndReturningDifferentErrorsNoLintAllWithSomethingElse() error {
+	var err1 = errors.New("1")
+	var err2 = errors.New("2")
+
+	if err1 != nil {
+		return err2 //nolint: foo,all ,bar
+	}
+
+	return nil
+}
+
+func CheckingAndReturningDifferentErrorsNoLintCorrecterr() error {
+	var err1 = errors.New("1")
+	var err2 = errors.New("2")
+
+	if err1 != nil {
+		return err2 //nolint:correcterr,foo
+	}
+
+	return nil
+}
+
+func AppendedUnrelatedErrNoLint() error {
+	var errs []error
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		errs = append(errs, other) //nolint:correcterr
+	}
+
+	return errors.Join(errs...)
+}
+
+// ----------------------------------------------------
+// Non-triggers
+
+func ErrorfWrap2() error {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+
+	if err1 != nil {
+		return fmt.Errorf("errors: %w, %w", err1, err2)
+	}
+
+	return nil
+}
+
+type someStruct struct {
+	val int
+	err error
+}
+
+var structs = []someStruct{}
+
+func ReturnFieldOfStruct() (int, error) {
+	for _, s := range structs {
+		if s.val > 1 {
+			return s.val, s.err
+		}
+	}
+
+	return 0, nil
+}
+
+func ReturnFieldOfStruct2() error {
+	cond1 := true
+	cond2 := true
+
+	if cond1 && cond2 {
+		return fmt.Errorf("%w, upload_id = %s", ExternalErrors.ExtError, 12)
+	}
+
+	return nil
+}
+
+func Correct() error {
+	var err1 = errors.New("1")
+	var err2 = errors.New("2")
+
+	if err1 != nil {
+		return err1
+	}
+
+	if err2 != nil {
+		return err2
+	}
+
+	return nil
+}
+
+func NilError() error {
+	var someError error
+	var anotherError error
+
+	if someError == nil {
+		return anotherError
+	}
+
+	return nil
+}
+
+func LengthOfSlice() error {
+	var slice []int
+	err := errors.New("empty")
+	if len(slice) == 0 {
+		return err
+	}
+
+	return nil
+}
+
+func NewErrorAfterCheck() error {
+	var err error
+	if err != nil {
+		return errors.New("some new error")
+	}
+
+	return nil
+}
+
+func IfTrue() error {
+	var err error
+	if true {
+		return err
+	}
+
+	return nil
+}
+
+func CompareNumbers() error {
+	a := 2
+	b := 3
+	var err error
+
+	if a != b {
+		return err
+	}
+
+	return nil
+}
+
+func DoubleWrap() error {
+	var err error
+	if err != nil {
+		return fmt.Errorf("error: %w", fmt.Errorf("error: %w", err))
+	}
+
+	return nil
+}
+
+func TripleFooWrap() error {
+	var err error
+	if err != nil {
+		return fooWrap(1, fooWrap(2, fooWrap(3, err, "c"), "b"), "a")
+	}
+
+	return nil
+}
+
+func ReturningWrappedMessage() error {
+	err := errors.New("some error")
+	if err != nil {
+		return fooWrap(1, errors.New("new error"), err.Error())
+	}
+
+	return nil
+}
+
+func ReturningWrappedMessage2() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil {
+		return fooWrap(1, errors.New("new error"), anotherErr.Error())
+	}
+
+	return nil
+}
+
+func ErrorsIsCorrect() error {
+	err := errors.New("original")
+	wrappedErr := fmt.Errorf("wrapped: %w", err)
+	if errors.Is(wrappedErr, err) {
+		return wrappedErr
+	}
+
+	return nil
+}
+
+func ErrorsIsNegatedCorrect() error {
+	err := errors.New("original")
+
+	if err != nil && !errors.Is(err, ExternalError) {
+		return fmt.Errorf("unexpected: %w", err)
+	}
+
+	return nil
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+
+func ErrorsAsCorrect() error {
+	err := errors.New("original")
+
+	var target timeoutError
+	if errors.As(err, &target) {
+		return target
+	}
+
+	return nil
+}
+
+func ErrorsAsTargetWrapped() error {
+	err := errors.New("original")
+
+	var target error
+	if errors.As(err, &target) {
+		return fmt.Errorf("wrapped: %w", target)
+	}
+
+	return nil
+}
+
+func FooCheckCorrect() error {
+	err := errors.New("some error")
+
+	if fooCheck(1, err, "a") {
+		return err
+	}
+
+	return nil
+}
+
+func FooCheckWrappedCorrect() error {
+	err := errors.New("some error")
+
+	if fooCheck(1, err, "a") {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	return nil
+}
+
+func FooCheckReturnMessageCorrect() error {
+	err := errors.New("some error")
+
+	if fooCheck(1, err, "a") {
+		return errors.New(err.Error())
+	}
+
+	return nil
+}
+
+func FooCheckReturnMessageCorrectWrapped() error {
+	err := errors.New("some error")
+
+	if fooCheck(1, err, "a") {
+		return fmt.Errorf("error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func RetryableCheckCorrect() error {
+	err := errors.New("some error")
+
+	if checkers.IsRetryable(err) {
+		return fmt.Errorf("retryable: %w", err)
+	}
+
+	return nil
+}
+
+func RetryCheckNotAlwaysChecking(attempt int) error {
+	err := errors.New("some error")
+	lastErr := errors.New("last error")
+
+	if shouldRetry(err, attempt) {
+		return lastErr
+	}
+
+	return nil
+}
+
+func SucceededCheckInverted() error {
+	err := errors.New("some error")
+
+	if checkers.Succeeded(err) {
+		return nil
+	}
+
+	return fmt.Errorf("failed: %w", err)
+}
+
+func ReturningErrAnnotatedBeforeCheck() error {
+	err := errors.New("some error")
+	annotated := wrappers.Annotate(err, "context")
+
+	if err != nil {
+		return annotated
+	}
+
+	return nil
+}
+
+func CheckedErrReplacingAnotherErr() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil {
+		return replaceErr(anotherErr, err)
+	}
+
+	return nil
+}
+
+func ReturningDroppedCheckedErr() error {
+	err := errors.New("some error")
+
+	if err != nil {
+		return logAndDrop(err)
+	}
+
+	return nil
+}
+
+func FooCheckReturnMessageWrong() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if fooCheck(1, err, "a") {
+		return fmt.Errorf("error: %s", anotherErr.Error())
+	}
+
+	return nil
+}
+
+func FooCheckCorrect2() (error, error) {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if fooCheck(1, err, "a") {
+		return err, anotherErr
+	}
+
+	return nil, nil
+}
+
+func CheckTwoErrorsCorrect() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil && anotherErr != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ReturnWrappedErrorMessage() error {
+	err := errors.New("some error")
+
+	if err != nil {
+		return errors.New(fooWrap(1, err, "a").Error())
+	}
+
+	return nil
+}
+
+func ReturnWrappedErrorMessage2() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil {
+		return errors.New(fooWrap(1, anotherErr, "a").Error())
+	}
+
+	return nil
+}
+
+func ErrorCheckedInOuterIfStatement() error {
+	err := errors.New("some error")
+	anotherErr := errors.New("another error")
+
+	if err != nil {
+		if anotherErr != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WrappingWithAssignmentBeforeReturning() error {
+	err := errors.New("error")
+	if err != nil {
+		wrappedErr := fmt.Errorf("wrapped: %w", err)
+
+		return wrappedErr
+	}
+
+	return nil
+}
+
+func WrappingWithDeclarationBeforeReturning() error {
+	err := errors.New("error")
+	if err != nil {
+		var wrappedErr error = fmt.Errorf("wrapped: %w", err)
+
+		return wrappedErr
+	}
+
+	return nil
+}
+
+func AssignCheckedErr() error {
+	err := errors.New("foo")
+	if err != nil {
+		tmpErr := err
+		err := errors.New("bar")
+		if err != nil {
+			return fmt.Errorf("errors: %s, %s", tmpErr, err)
+		}
+	}
+
+	return nil
+}
+
+func AssignCheckedErrViaDeclaration() error {
+	err := errors.New("foo")
+	if err != nil {
+		var tmpErr error = err
+		err := errors.New("bar")
+		if err != nil {
+			return fmt.Errorf("errors: %s, %s", tmpErr, err)
+		}
+	}
+
+	return nil
+}
+
+func PkgError() error {
+	err := errors.New("error")
+	if err != nil {
+		return ExternalError
+	}
+
+	return nil
+}
+
+func WrapCycle() error {
+	err := errors.New("error")
+	if err != nil {
+		var wrappedB error
+		wrappedA := fmt.Errorf("wrapped: %w", wrappedB)
+		wrappedB = fmt.Errorf("wrapped: %w", wrappedA)
+		wrappedB = fmt.Errorf("wrapped: %w", wrappedA)
+		wrappedA = fmt.Errorf("wrapped: %w", wrappedB)
+
+		return wrappedB
+	}
+
+	return nil
+}
+
+func NestedErrorCheckReturnsOuterError() error {
+	err := errors.New("some error")
+
+	if err != nil {
+		if innerErr := errors.New("inner"); innerErr != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NestedErrorCheckReturnsOuterErrorMessage() error {
+	err := errors.New("some error")
+
+	if err != nil {
+		if innerErr := errors.New("inner"); innerErr != nil {
+			return fmt.Errorf("%w: %s", err, innerErr.Error())
+		}
+	}
+
+	return nil
+}
+
+func NestedErrorCheckReturnsOuterErrorMessage2() error {
+	err := errors.New("some error")
+
+	if err != nil {
+		if innerErr := errors.New("inner"); innerErr != nil {
+			return fmt.Errorf("wrapped: %s", err.Error())
+		}
+	}
+
+	return nil
+}
 
-	if err1 != nil {
-		return err2 //nolint
+func Foobar() error {
+	_, err := doSmth()
+	if err != nil {
+		_, errB := doSmth()
+
+		return errB
 	}
 
 	return nil
 }
 
-func CheckingAndReturningDifferentErrorsNoLintAll() error {
-	var err1 = errors.New("1")
-	var err2 = errors.New("2")
+func More() error {
+	_, err := doSmth()
+	if err != nil {
+		if _, errB := doSmth(); errB != nil {
+			return fmt.Errorf("wrapped: %w", err)
+		}
 
-	if err1 != nil {
-		return err2 //nolint:all
+		return nil
 	}
 
 	return nil
 }
 
-func CheckingAndReturningDifferentErrorsNoLintAllWithSomethingElse() error {
-	var err1 = errors.New("1")
-	var err2 = errors.New("2")
+func ShadowingClosureParam() error {
+	err := errors.New("outer")
+	_ = err
+	anotherErr := errors.New("another")
 
-	if err1 != nil {
-		return err2 //nolint: foo,all ,bar
+	if anotherErr != nil {
+		return paramWrapper(anotherErr, func(err error) error {
+			if anotherErr != nil {
+				return err
+			}
+
+			return nil
+		})
 	}
 
 	return nil
 }
 
-func CheckingAndReturningDifferentErrorsNoLintCorrecterr() error {
-	var err1 = errors.New("1")
-	var err2 = errors.New("2")
+func ElseIfChecksAnotherErrorCorrect() error {
+	err := errors.New("error")
+	txErr := errors.New("tx error")
 
-	if err1 != nil {
-		return err2 //nolint:correcterr,foo
+	if err != nil {
+		return err
+	} else if txErr != nil {
+		return fmt.Errorf("tx: %w", txErr)
 	}
 
 	return nil
 }
 
-// ----------------------------------------------------
-// Non-triggers
+func ElseBranchOfCheck() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
 
-func ErrorfWrap2() error {
-	err1 := errors.New("1")
-	err2 := errors.New("2")
+	if err != nil {
+		return err
+	} else {
+		return anotherErr
+	}
+}
 
-	if err1 != nil {
-		return fmt.Errorf("errors: %w, %w", err1, err2)
+func InvertedEarlyReturnCorrect() error {
+	err := errors.New("error")
+
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("wrapped: %w", err)
+}
+
+func InvertedCheckWithoutEarlyReturn() error {
+	err := errors.New("error")
+	anotherErr := errors.New("another")
+
+	if err == nil {
+		fmt.Println("ok")
+	}
+
+	return anotherErr
+}
+
+func InitDeclaredErrOutOfScopeAfterEarlyReturn() (int, error) {
+	loadErr := errors.New("load")
+
+	if v, err := doSmth(); err == nil {
+		return v, nil
+	}
+
+	return 0, loadErr
+}
+
+func SentinelComparedBeforeEarlyReturn(prev error) error {
+	_, err := doSmth()
+	if prev != ExternalError {
+		return prev
+	}
+
+	return err
+}
+
+func NilErrReassigned() error {
+	_, err := doSmth()
+
+	if err == nil {
+		_, err = doSmth()
+
+		return err
 	}
 
 	return nil
 }
 
-type someStruct struct {
-	val int
-	err error
+func EitherErrorCheckedCorrect() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	if errA != nil || errB != nil {
+		return errB
+	}
+
+	return nil
 }
 
-var structs = []someStruct{}
+func ReversedNilCheckCorrect() error {
+	err := errors.New("error")
 
-func ReturnFieldOfStruct() (int, error) {
-	for _, s := range structs {
-		if s.val > 1 {
-			return s.val, s.err
+	if nil != err && !errors.Is(err, ExternalError) {
+		return fmt.Errorf("wrapped: %w", err)
+	}
+
+	return nil
+}
+
+func ReturningErrAfterEarlyReturn() error {
+	err := errors.New("error")
+
+	if err != nil {
+		return fmt.Errorf("wrapped: %w", err)
+	}
+
+	return err
+}
+
+func NilOrFlag(retryable bool) error {
+	err := errors.New("error")
+
+	if err == nil || retryable {
+		return err
+	}
+
+	return nil
+}
+
+func SwitchCaseCorrect() error {
+	err := errors.New("error")
+
+	switch {
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
+func SwitchTagCorrect() error {
+	err := errors.New("error")
+
+	switch err {
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("wrap: %w", err)
+	}
+}
+
+func SwitchFallthroughFromCheck(retryable bool) error {
+	err := errors.New("error")
+
+	switch {
+	case err != nil:
+		fallthrough
+	case retryable:
+		return err
+	}
+
+	return nil
+}
+
+func SwitchFallthroughIntoDefault() error {
+	err := errors.New("error")
+
+	switch {
+	case err != nil:
+		fallthrough
+	default:
+		return err
+	}
+}
+
+func SwitchFallthroughIntoNilCase() error {
+	_, err := doSmth()
+
+	switch {
+	case err != nil:
+		fallthrough
+	case err == nil:
+		return err
+	}
+
+	return nil
+}
+
+func SwitchInitDeclaredErrIsFresh() error {
+	anotherErr := errors.New("another")
+
+	switch _, err := doSmth(); {
+	case anotherErr != nil:
+		return err
+	}
+
+	return nil
+}
+
+func IfInitDeclaredErrIsFresh(cond bool) error {
+	err := errors.New("error")
+
+	if err != nil {
+		if _, initErr := doSmth(); cond {
+			return initErr
 		}
 	}
 
-	return 0, nil
+	return nil
 }
 
-func ReturnFieldOfStruct2() error {
-	cond1 := true
-	cond2 := true
+func SwitchInitCheckedErr() error {
+	switch _, err := doSmth(); {
+	case err != nil:
+		return err
+	}
 
-	if cond1 && cond2 {
-		return fmt.Errorf("%w, upload_id = %s", ExternalErrors.ExtError, 12)
+	return nil
+}
+
+func IfInitWrapsCheckedError() error {
+	err := errors.New("error")
+
+	if wrapped := fmt.Errorf("wrapped: %w", err); err != nil {
+		return wrapped
 	}
 
 	return nil
 }
 
-func Correct() error {
-	var err1 = errors.New("1")
-	var err2 = errors.New("2")
+func TypeAssertedCheckedErrInInit() error {
+	err := errors.New("error")
 
-	if err1 != nil {
-		return err1
+	if err != nil {
+		if appErr, ok := err.(*AppError); ok {
+			return appErr
+		}
+
+		return err
 	}
 
-	if err2 != nil {
-		return err2
+	return nil
+}
+
+func TypeAssertedCheckedErr() error {
+	err := errors.New("error")
+
+	if err != nil {
+		appErr, ok := err.(*AppError)
+		if ok {
+			return appErr
+		}
+
+		return err
 	}
 
 	return nil
 }
 
-func NilError() error {
-	var someError error
-	var anotherError error
+func RangeValueChecked(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
 
-	if someError == nil {
-		return anotherError
+	return nil
+}
+
+func CustomErrorChecked() error {
+	appErr := newAppError()
+
+	if appErr != nil {
+		return appErr
 	}
 
 	return nil
 }
 
-func LengthOfSlice() error {
-	var slice []int
-	err := errors.New("empty")
-	if len(slice) == 0 {
+func FieldChecked(res result) error {
+	if res.Err != nil {
+		return fmt.Errorf("result: %w", res.Err)
+	}
+
+	return nil
+}
+
+func ParenthesizedFieldChecked(res *result) error {
+	if (*res).Err != nil {
+		return res.Err
+	}
+
+	return nil
+}
+
+func FieldReassigned(res result) error {
+	if res.Err == nil {
+		res = result{Err: errors.New("retry")}
+
+		return res.Err
+	}
+
+	return nil
+}
+
+func FieldOfCheckedErrReturned() error {
+	opErr := newOpError()
+
+	if opErr != nil {
+		return opErr.Err
+	}
+
+	return nil
+}
+
+func PackageLevelFieldReturned() error {
+	err := errors.New("local")
+
+	if err != nil {
+		return ExternalErrors.ExtError
+	}
+
+	return nil
+}
+
+func RowsErrChecked() error {
+	rows := newRows()
+
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	if err := rows.Err(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func NewErrorAfterCheck() error {
-	var err error
+func RollbackErrReturned() error {
+	tx := beginTx()
+	err := errors.New("exec")
+
 	if err != nil {
-		return errors.New("some new error")
+		return tx.Rollback()
 	}
 
 	return nil
 }
 
-func IfTrue() error {
-	var err error
-	if true {
-		return err
+func CtxErrCheckedWrapped(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("canceled: %w", ctx.Err())
 	}
 
 	return nil
 }
 
-func CompareNumbers() error {
-	a := 2
-	b := 3
-	var err error
+func CtxErrCheckedCauseReturned(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return context.Cause(ctx)
+	}
 
-	if a != b {
+	return nil
+}
+
+func CtxErrAssignedAndChecked(ctx context.Context) error {
+	err := ctx.Err()
+
+	if ctx.Err() != nil {
 		return err
 	}
 
 	return nil
 }
 
-func DoubleWrap() error {
-	var err error
-	if err != nil {
-		return fmt.Errorf("error: %w", fmt.Errorf("error: %w", err))
+func CtxErrNilCheckNotStable(ctx context.Context) error {
+	if ctx.Err() == nil {
+		doWork()
+
+		return ctx.Err()
 	}
 
 	return nil
 }
 
-func TripleFooWrap() error {
-	var err error
-	if err != nil {
-		return fooWrap(1, fooWrap(2, fooWrap(3, err, "c"), "b"), "a")
+func AppendedCheckedErr(items []string) error {
+	var errs []error
+
+	for range items {
+		if err := errors.New("item"); err != nil {
+			errs = append(errs, fmt.Errorf("item: %w", err))
+			continue
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func ReturningWrappedMessage() error {
-	err := errors.New("some error")
+func JoinedErrsWithCheckedErr() error {
+	var errs []error
+	err := errors.New("1")
+	errs = append(errs, err)
+
 	if err != nil {
-		return fooWrap(1, errors.New("new error"), err.Error())
+		return errors.Join(errs...)
 	}
 
 	return nil
 }
 
-func ReturningWrappedMessage2() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func JoinedCheckedErr() error {
+	var errs []error
+	err := errors.New("1")
 
 	if err != nil {
-		return fooWrap(1, errors.New("new error"), anotherErr.Error())
+		return errors.Join(append(errs, err)...)
 	}
 
 	return nil
 }
 
-func ErrorsIsCorrect() error {
-	err := errors.New("original")
-	wrappedErr := fmt.Errorf("wrapped: %w", err)
-	if errors.Is(wrappedErr, err) {
-		return wrappedErr
+func MultierrCombinedCheckedErr() error {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+
+	if err1 != nil {
+		return multierr.Combine(err2, err1)
 	}
 
 	return nil
 }
 
-func ErrorsIsWrong() error {
-	err := errors.New("original")
-	anotherErr := errors.New("another")
+func MultierrorAppendedCheckedErr() error {
+	var result *multierror.Error
+	err := errors.New("1")
 
-	wrappedErr := fmt.Errorf("wrapped: %w", err)
-	if errors.Is(wrappedErr, err) {
-		return anotherErr
+	if err != nil {
+		result = multierror.Append(result, err)
+
+		return result.ErrorOrNil()
 	}
 
 	return nil
 }
 
-func FooCheckCorrect() error {
-	err := errors.New("some error")
+func PkgErrorsWrapChecked() error {
+	err := errors.New("1")
 
-	if fooCheck(1, err, "a") {
-		return err
+	if err != nil {
+		return pkgerrors.WithStack(pkgerrors.Wrap(err, "failed"))
 	}
 
 	return nil
 }
 
-func FooCheckWrappedCorrect() error {
-	err := errors.New("some error")
+func PkgErrorsErrorfFormattingChecked() error {
+	err := errors.New("1")
 
-	if fooCheck(1, err, "a") {
-		return fmt.Errorf("error: %w", err)
+	if err != nil {
+		return pkgerrors.Errorf("failed: %v", err)
 	}
 
 	return nil
 }
 
-func FooCheckReturnMessageCorrect() error {
-	err := errors.New("some error")
+func CockroachCombineChecked() error {
+	err := errors.New("1")
+	other := errors.New("2")
 
-	if fooCheck(1, err, "a") {
-		return errors.New(err.Error())
+	if err != nil {
+		return crdberrors.WithHint(crdberrors.CombineErrors(other, err), "retry later")
 	}
 
 	return nil
 }
 
-func FooCheckReturnMessageCorrectWrapped() error {
-	err := errors.New("some error")
+func XerrorsOpaqueChecked() error {
+	err := errors.New("1")
 
-	if fooCheck(1, err, "a") {
-		return fmt.Errorf("error: %s", err.Error())
+	if err != nil {
+		return xerrors.Opaque(err)
 	}
 
 	return nil
 }
 
-func FooCheckWrong() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func GRPCStatusOfChecked() error {
+	err := errors.New("1")
 
-	if fooCheck(1, err, "a") {
-		return anotherErr
+	if err != nil {
+		return status.Convert(err).Err()
 	}
 
 	return nil
 }
 
-func FooCheckWrappedWrong() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func GRPCStatusIsFresh() error {
+	err := errors.New("1")
 
-	if fooCheck(1, err, "a") {
-		return fmt.Errorf("error: %w", anotherErr)
+	if err != nil {
+		return status.Error(codes.Internal, "internal error")
 	}
 
 	return nil
 }
 
-func FooCheckReturnMessageWrong() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func ErrorfWrapsCheckedFormatsOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
 
-	if fooCheck(1, err, "a") {
-		return fmt.Errorf("error: %s", anotherErr.Error())
+	if err != nil {
+		return fmt.Errorf("%v: %w", other, err)
 	}
 
 	return nil
 }
 
-func FooCheckCorrect2() (error, error) {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func ErrorfIndexedVerbsCorrect(width int) error {
+	err := errors.New("1")
+	other := errors.New("2")
 
-	if fooCheck(1, err, "a") {
-		return err, anotherErr
+	if err != nil {
+		return fmt.Errorf("%*d %[4]v: %[3]w", width, 42, err, other)
 	}
 
-	return nil, nil
+	return nil
 }
 
-func CheckTwoErrorsCorrect() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func ErrorfWrapperWrapsChecked() error {
+	err := errors.New("1")
+	other := errors.New("2")
 
-	if err != nil && anotherErr != nil {
-		return err
+	if err != nil {
+		return errorfWrapper("%v: %w", other, err)
 	}
 
 	return nil
 }
 
-func ReturnWrappedErrorMessage() error {
-	err := errors.New("some error")
+func ErrorfOtherMessageOnly() error {
+	err := errors.New("1")
+	other := errors.New("2")
 
 	if err != nil {
-		return errors.New(fooWrap(1, err, "a").Error())
+		return fmt.Errorf("%s", other.Error())
 	}
 
 	return nil
 }
 
-func ReturnWrappedErrorMessage2() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func BareReturnOfCheckedErr() (n int, err error) {
+	n, err = doSmth()
 
 	if err != nil {
-		return errors.New(fooWrap(1, anotherErr, "a").Error())
+		return
 	}
 
-	return nil
+	return
 }
 
-func ErrorCheckedInOuterIfStatement() error {
-	err := errors.New("some error")
-	anotherErr := errors.New("another error")
+func BareReturnOfUnassignedResult(matches []string) (m []string, e error) {
+	m = matches
 
+	n, err := doSmth()
 	if err != nil {
-		if anotherErr != nil {
-			return err
-		}
+		return // ignore the error
 	}
 
-	return nil
+	return append(m, fmt.Sprint(n)), nil
 }
 
-func WrappingWithAssignmentBeforeReturning() error {
-	err := errors.New("error")
-	if err != nil {
-		wrappedErr := fmt.Errorf("wrapped: %w", err)
+func BareReturnAfterWrappingChecked() (retErr error) {
+	err := errors.New("1")
 
-		return wrappedErr
+	if err != nil {
+		retErr = fmt.Errorf("failed: %w", err)
+		return
 	}
 
 	return nil
 }
 
-func WrappingWithDeclarationBeforeReturning() error {
-	err := errors.New("error")
-	if err != nil {
-		var wrappedErr error = fmt.Errorf("wrapped: %w", err)
+func BareReturnOverwrittenByDefer() (err error) {
+	r := newRows()
+	defer func() {
+		if closeErr := r.Err(); closeErr != nil {
+			err = closeErr
+		}
+	}()
 
-		return wrappedErr
+	if txErr := errors.New("tx"); txErr != nil {
+		return
 	}
 
 	return nil
 }
 
-func AssignCheckedErr() error {
-	err := errors.New("foo")
-	if err != nil {
-		tmpErr := err
-		err := errors.New("bar")
-		if err != nil {
-			return fmt.Errorf("errors: %s, %s", tmpErr, err)
+func BareReturnInClosure() (err error) {
+	func() {
+		if txErr := errors.New("tx"); txErr != nil {
+			return
 		}
-	}
+	}()
 
 	return nil
 }
 
-func AssignCheckedErrViaDeclaration() error {
-	err := errors.New("foo")
-	if err != nil {
-		var tmpErr error = err
-		err := errors.New("bar")
-		if err != nil {
-			return fmt.Errorf("errors: %s, %s", tmpErr, err)
+func AssigningCheckedToOuterVar(items []string) error {
+	var firstErr error
+
+	for range items {
+		if err := errors.New("item"); err != nil {
+			firstErr = fmt.Errorf("item: %w", err)
+			break
 		}
 	}
 
-	return nil
+	return firstErr
 }
 
-func PkgError() error {
-	err := errors.New("error")
+func AssigningFreshErrToOuterVar() error {
+	var retErr error
+	err := errors.New("1")
+
 	if err != nil {
-		return ExternalError
+		retErr = errors.New("fresh")
 	}
 
-	return nil
+	return retErr
 }
 
-func WrapCycle() error {
-	err := errors.New("error")
-	if err != nil {
-		var wrappedB error
-		wrappedA := fmt.Errorf("wrapped: %w", wrappedB)
-		wrappedB = fmt.Errorf("wrapped: %w", wrappedA)
-		wrappedB = fmt.Errorf("wrapped: %w", wrappedA)
-		wrappedA = fmt.Errorf("wrapped: %w", wrappedB)
+func AssigningOtherToInnerVar() error {
+	other := errors.New("other")
 
-		return wrappedB
+	if err := errors.New("1"); err != nil {
+		var inner error
+		inner = other
+		_ = inner
 	}
 
 	return nil
 }
 
-func NestedErrorCheckReturnsOuterError() error {
-	err := errors.New("some error")
+func UnwrappingCheckedErrInPlace() error {
+	_, err := doSmth()
 
 	if err != nil {
-		if innerErr := errors.New("inner"); innerErr != nil {
-			return err
+		if opErr, ok := err.(*opError); ok {
+			err = opErr.Err
 		}
+
+		return err
 	}
 
 	return nil
 }
 
-func NestedErrorCheckReturnsOuterErrorMessage() error {
-	err := errors.New("some error")
+func UnwrappingCheckedErrInPlaceWithoutInit() error {
+	_, err := doSmth()
 
 	if err != nil {
-		if innerErr := errors.New("inner"); innerErr != nil {
-			return fmt.Errorf("%w: %s", err, innerErr.Error())
+		opErr, ok := err.(*opError)
+		if ok {
+			err = opErr.Err
 		}
+
+		return err
 	}
 
 	return nil
 }
 
-func NestedErrorCheckReturnsOuterErrorMessage2() error {
-	err := errors.New("some error")
+func AssigningFieldOfCheckedErr() (retErr error) {
+	opErr := newOpError()
+
+	if opErr != nil {
+		retErr = opErr.Err
+	}
+
+	return retErr
+}
+
+func WrappingInBothBranches(verbose bool) error {
+	err := errors.New("1")
+	var wrapped error
+
+	if verbose {
+		wrapped = fmt.Errorf("failed with details: %w", err)
+	} else {
+		wrapped = fmt.Errorf("failed: %w", err)
+	}
 
 	if err != nil {
-		if innerErr := errors.New("inner"); innerErr != nil {
-			return fmt.Errorf("wrapped: %s", err.Error())
-		}
+		return wrapped
 	}
 
 	return nil
 }
 
-func Foobar() error {
-	_, err := doSmth()
-	if err != nil {
-		_, errB := doSmth()
+func RewrappingWrapper() error {
+	err := errors.New("1")
 
-		return errB
+	wrapped := fmt.Errorf("inner: %w", err)
+	wrapped = fmt.Errorf("outer: %w", wrapped)
+
+	if err != nil {
+		return wrapped
 	}
 
 	return nil
 }
 
-func More() error {
-	_, err := doSmth()
-	if err != nil {
-		if _, errB := doSmth(); errB != nil {
-			return fmt.Errorf("wrapped: %w", err)
+func WrappingInPreviousIteration() error {
+	err := errors.New("1")
+	var wrapped error
+
+	for i := range 3 {
+		if i > 0 && err != nil {
+			return wrapped
 		}
 
-		return nil
+		wrapped = fmt.Errorf("attempt %d: %w", i, err)
 	}
 
 	return nil
@@ -753,14 +2523,108 @@ func closureWrapper(fn func() error) error {
 	return fn()
 }
 
+func paramWrapper(err error, fn func(error) error) error {
+	return fn(err)
+}
+
 func fooWrap(_ int, err error, _ string) error {
 	return err
 }
 
-func fooCheck(_ int, err error, _ string) bool {
+func fooCheck(_ int, err error, _ string) bool { // want fooCheck:`errChecker\(true:\[1\] false:\[\]\)`
+	return err != nil
+}
+
+func isNotFound(err error) bool { // want isNotFound:`errChecker\(true:\[0\] false:\[0\]\)`
+	return errors.Is(err, ExternalError)
+}
+
+func shouldRetry(err error, attempt int) bool {
+	if attempt == 0 {
+		return true
+	}
+
 	return err != nil
 }
 
+func logAndDrop(err error) error { // want logAndDrop:`errFlow\(0:dropped\)`
+	fmt.Println("dropping:", err)
+
+	return errors.New("dropped")
+}
+
+func replaceErr(old, replacement error) error { // want replaceErr:`errFlow\(0:dropped 1:wrapped\)`
+	fmt.Println("replacing:", old)
+
+	return replacement
+}
+
+func errorfWrapper(format string, args ...any) error {
+	return fmt.Errorf(format, args...)
+}
+
 func doSmth() (int, error) {
 	return 0, errors.New("doSmth failed")
 }
+
+type AppError struct{}
+
+func (*AppError) Error() string { return "app error" }
+
+func newAppError() *AppError {
+	return &AppError{}
+}
+
+type CodedError interface {
+	error
+	Code() int
+}
+
+func newCodedError() CodedError {
+	return nil
+}
+
+type result struct {
+	Err error
+}
+
+type response struct {
+	Header result
+	Body   result
+}
+
+func newResult() result {
+	return result{Err: errors.New("result")}
+}
+
+type opError struct {
+	Err error
+}
+
+func (e *opError) Error() string { return "op: " + e.Err.Error() }
+
+func newOpError() *opError {
+	return &opError{Err: errors.New("op")}
+}
+
+type rows struct {
+	err error
+}
+
+func newRows() *rows {
+	return &rows{}
+}
+
+func (r *rows) Next() bool { return false }
+
+func (r *rows) Err() error { return r.err }
+
+type tx struct{}
+
+func beginTx() *tx {
+	return &tx{}
+}
+
+func (*tx) Rollback() error { return nil }
+
+func doWork() {}
@@ -0,0 +1,15 @@
+package related
+
+import "errors"
+
+func AssignedAfterDeclaration() error {
+	txErr := errors.New("tx error")
+	var err error
+	err = errors.New("error")
+
+	if txErr != nil {
+		return err // want "checked `txErr` but returned `err`"
+	}
+
+	return nil
+}
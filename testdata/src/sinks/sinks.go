@@ -0,0 +1,130 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+type Span struct{}
+
+func (*Span) RecordError(err error) {}
+
+func respond(ctx context.Context, err error) {}
+
+func notify(err error) {}
+
+func HTTPErrorWithOther(w http.ResponseWriter) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		http.Error(w, other.Error(), http.StatusInternalServerError) // want "checked `err` but passed `other` to http\\.Error"
+	}
+}
+
+func HTTPErrorWithChecked(w http.ResponseWriter) {
+	err := errors.New("1")
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func FatalWithOther(t *testing.T) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		t.Fatal(other) // want "checked `err` but passed `other` to t\\.Fatal"
+	}
+
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+}
+
+func LogWithOther() {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		slog.Error("failed", slog.Any("err", other)) // want "checked `err` but passed `other` to slog\\.Error"
+	}
+
+	if err != nil {
+		log.Fatalf("failed: %v", fmt.Errorf("wrapped: %w", other)) // want "checked `err` but passed `other` wrapped in fmt\\.Errorf to log\\.Fatalf"
+	}
+
+	if err != nil {
+		log.Fatal(fmt.Errorf("failed after %v", other)) // want "checked `err` but passed `other` formatted in fmt\\.Errorf to log\\.Fatal"
+	}
+}
+
+func PanicWithOther() {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		panic(other) // want "checked `err` but panicked with `other`"
+	}
+}
+
+func SendOther(errCh chan<- error) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		errCh <- other // want "checked `err` but sent `other` to `errCh`"
+	}
+
+	if err != nil {
+		errCh <- fmt.Errorf("wrapped: %w", err)
+	}
+
+	if err != nil {
+		errCh <- other //nolint:correcterr
+	}
+}
+
+func SendOtherToIndexedChannel(errChs []chan<- error, i int) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		errChs[i%2] <- other // want "checked `err` but sent `other` to `errChs\\[i % 2\\]`"
+	}
+}
+
+func LoggerFatallnWithOther(logger *log.Logger) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		logger.Fatalln(other) // want "checked `err` but passed `other` to logger\\.Fatalln"
+	}
+
+	if err != nil {
+		logger.Panicln(other) // want "checked `err` but passed `other` to logger\\.Panicln"
+	}
+}
+
+func CustomSinks(ctx context.Context, span *Span) {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		span.RecordError(other) // want "checked `err` but passed `other` to span\\.RecordError"
+		respond(ctx, other)     // want "checked `err` but passed `other` to respond"
+		notify(other)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		respond(ctx, err)
+	}
+}
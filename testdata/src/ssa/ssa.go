@@ -0,0 +1,505 @@
+package ssa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Triggers
+
+func ReturnsOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return other // want "checked `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func WrapsOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return fmt.Errorf("failed: %w", other) // want "checked `err` but wrapped `other` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ReturnsOtherAfterGoto() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		goto fail
+	}
+
+	return nil
+
+fail:
+	return other // want "checked `err` but returned `other`"
+}
+
+func FormatsOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return fmt.Errorf("failed after %v", other) // want "checked `err` but formatted `other` in fmt\\.Errorf"
+	}
+
+	return nil
+}
+
+func ReturnsOtherInEitherCheck() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+	other := errors.New("c")
+
+	if errA != nil || errB != nil {
+		return other // want "checked `errA` or `errB` but returned `other`"
+	}
+
+	return nil
+}
+
+func ReturnsOtherInTaggedSwitch() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	switch err {
+	case ErrNotFound:
+		return other // want "checked `ErrNotFound` or `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func ReturnsOtherAssignedInEveryBranch(cond bool) error {
+	err := errors.New("1")
+	a, b := errors.New("a"), errors.New("b")
+
+	other := a
+	if cond {
+		other = b
+	}
+
+	if err != nil {
+		return other // want "checked `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func ReturnsOtherFromLoop(errs []error) error {
+	err := errors.New("1")
+
+	for _, other := range errs {
+		if err != nil {
+			return other // want "checked `err` but returned `other`"
+		}
+	}
+
+	return nil
+}
+
+func ReturnsCapturedOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	return run(func() error {
+		if err != nil {
+			return other // want "checked `err` but returned `other`"
+		}
+
+		return nil
+	})
+}
+
+func ClosureReturnsOther() error {
+	return run(func() error {
+		err := errors.New("1")
+		other := errors.New("2")
+
+		if err != nil {
+			return other // want "checked `err` but returned `other`"
+		}
+
+		return nil
+	})
+}
+
+func ReturnsOtherField() error {
+	req, res := newResult(), newResult()
+
+	if res.Err != nil {
+		return req.Err // want "checked `res.Err` but returned `req.Err`"
+	}
+
+	return nil
+}
+
+func ReturnsUnassignedErr() error {
+	err := errors.New("1")
+	var other error
+
+	if err != nil {
+		return other // want "checked `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func ReturnsErrKnownToBeNil() error {
+	err := errors.New("1")
+
+	if err == nil {
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func ReturnsErrKnownToBeNilWithDefer() error {
+	defer cleanup()
+
+	err := errors.New("1")
+
+	if err == nil {
+		return err // want "returned `err` which is known to be nil here"
+	}
+
+	return nil
+}
+
+func BareReturnOfOther() (err error) {
+	err = errors.New("1")
+
+	if txErr := errors.New("tx"); txErr != nil {
+		return // want "checked `txErr` but returned `err`"
+	}
+
+	return nil
+}
+
+func ReturnsOtherWithDefer() error {
+	defer cleanup()
+
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return other // want "checked `err` but returned `other`"
+	}
+
+	return nil
+}
+
+func ReturnsTypeAssertedOther() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	nf, ok := other.(*notFoundError)
+	if err != nil && ok {
+		return nf // want "checked `err` but returned `nf`"
+	}
+
+	return nil
+}
+
+func ReturnsCheckedMethodCallInOtherCheck() error {
+	r := &rows{}
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	if err := errors.New("1"); err != nil {
+		return r.Err() // want "checked `err` but returned `r.Err\\(\\)`"
+	}
+
+	return nil
+}
+
+// Non-triggers
+
+func ReturnsChecked() error {
+	err := errors.New("1")
+
+	if err != nil {
+		return fmt.Errorf("failed: %w", err)
+	}
+
+	return nil
+}
+
+func ReturnsCheckedAfterGoto() error {
+	err := errors.New("1")
+
+	if err != nil {
+		goto fail
+	}
+
+	return nil
+
+fail:
+	return err
+}
+
+func ReturnsFreshErr() error {
+	err := errors.New("1")
+
+	if err != nil {
+		return errors.New("fresh")
+	}
+
+	return nil
+}
+
+func ReturnsErrAfterEarlyReturn() error {
+	err := errors.New("1")
+
+	if err != nil {
+		return fmt.Errorf("failed: %w", err)
+	}
+
+	return err
+}
+
+func ReturnsErrAssignedInsideCheck() error {
+	err := errors.New("1")
+
+	if err != nil {
+		other := fmt.Errorf("failed: %w", err)
+
+		return other
+	}
+
+	return nil
+}
+
+func ReturnsOtherReassignedBeforeReturn(cond bool) error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		if cond {
+			other = err
+		}
+
+		return other
+	}
+
+	return nil
+}
+
+func ReturnsCheckedFromLoop(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ReturnsCheckedInEitherCheck() error {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	if errA != nil || errB != nil {
+		return errors.Join(errA, errB)
+	}
+
+	return nil
+}
+
+func ReturnsCapturedChecked() error {
+	err := errors.New("1")
+
+	return run(func() error {
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func ReturnsErrorsAsTarget() error {
+	err := errors.New("1")
+
+	var target *notFoundError
+	if errors.As(err, &target) {
+		return target
+	}
+
+	return nil
+}
+
+func ReturnsCheckedMethodCall(r *rows) error {
+	if r.Err() != nil {
+		return fmt.Errorf("rows: %w", r.Err())
+	}
+
+	return nil
+}
+
+func BareReturnOverwrittenByDefer(r *rows) (err error) {
+	defer func() {
+		if closeErr := r.Err(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	if txErr := errors.New("tx"); txErr != nil {
+		return
+	}
+
+	return nil
+}
+
+func ReturnsNilWithDefer() error {
+	defer cleanup()
+
+	if _, err := stat(); err == nil {
+		return errors.New("not a directory")
+	}
+
+	return nil
+}
+
+func ReturnsCheckedWithDefer() error {
+	defer cleanup()
+
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return fmt.Errorf("failed: %w", err)
+	}
+
+	return other
+}
+
+func ReturnsOtherOverwrittenByDefer(r *rows) (err error) {
+	defer func() {
+		if closeErr := r.Err(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	other := errors.New("2")
+
+	if txErr := errors.New("tx"); txErr != nil {
+		return other
+	}
+
+	return nil
+}
+
+func ReturnsTypeAssertedChecked() error {
+	err := errors.New("1")
+
+	nf, ok := err.(*notFoundError)
+	if err != nil && ok {
+		return nf
+	}
+
+	return nil
+}
+
+func ReturnsFieldOfChecked() error {
+	opErr := newOpError()
+
+	if opErr != nil {
+		return opErr.Err
+	}
+
+	return nil
+}
+
+func ReturnsMethodCallResult() error {
+	t := beginTx()
+
+	if err := errors.New("1"); err != nil {
+		return t.Rollback()
+	}
+
+	return nil
+}
+
+func ReturnsOtherAfterInitDeclaredCheck() (int, error) {
+	loadErr := errors.New("load")
+
+	if v, err := stat(); err == nil {
+		return v, nil
+	}
+
+	return 0, loadErr
+}
+
+func Suppressed() error {
+	err := errors.New("1")
+	other := errors.New("2")
+
+	if err != nil {
+		return other //nolint:correcterr
+	}
+
+	return nil
+}
+
+var ErrNotFound = errors.New("not found")
+
+type notFoundError struct{}
+
+func (*notFoundError) Error() string { return "not found" }
+
+type result struct {
+	Err error
+}
+
+func newResult() *result {
+	return &result{}
+}
+
+type rows struct {
+	err error
+}
+
+func (r *rows) Err() error {
+	return r.err
+}
+
+type opError struct {
+	Err error
+}
+
+func (e *opError) Error() string { return e.Err.Error() }
+
+func newOpError() *opError {
+	return &opError{Err: errors.New("op")}
+}
+
+type tx struct{}
+
+func beginTx() *tx {
+	return &tx{}
+}
+
+func (*tx) Rollback() error {
+	return nil
+}
+
+func cleanup() {}
+
+func stat() (int, error) {
+	return 0, nil
+}
+
+func run(f func() error) error {
+	return f()
+}
@@ -0,0 +1,54 @@
+package wrappers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+var lastErr error
+
+func Annotate(err error, msg string) error {
+	return &annotated{err: err, msg: msg}
+}
+
+func Drop(err error) error {
+	if err != nil {
+		log.Printf("dropping: %v", err)
+	}
+
+	return errors.New("dropped")
+}
+
+func Stash(err error) error {
+	lastErr = err
+
+	return errors.New("stashed")
+}
+
+func Record(rec *Recorder, err error) error {
+	rec.Add(err)
+
+	return fmt.Errorf("recorded %d errors", len(rec.errs))
+}
+
+type Recorder struct {
+	errs []error
+}
+
+func (r *Recorder) Add(err error) {
+	r.errs = append(r.errs, err)
+}
+
+type annotated struct {
+	err error
+	msg string
+}
+
+func (a *annotated) Error() string {
+	return a.msg + ": " + a.err.Error()
+}
+
+func (a *annotated) Unwrap() error {
+	return a.err
+}
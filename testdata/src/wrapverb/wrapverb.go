@@ -0,0 +1,52 @@
+package wrapverb
+
+import (
+	"errors"
+	"fmt"
+)
+
+func FormattedWithV() error {
+	err := errors.New("error")
+
+	if err != nil {
+		return fmt.Errorf("open: %v", err) // want "checked `err` but formatted it with %v instead of wrapping it with %w"
+	}
+
+	return nil
+}
+
+func FormattedWithS(name string) error {
+	err := errors.New("error")
+
+	if err != nil {
+		return fmt.Errorf(`open %q: %[2]s`, name, err) // want "checked `err` but formatted it with %s instead of wrapping it with %w"
+	}
+
+	return nil
+}
+
+func Wrapped() error {
+	err := errors.New("error")
+
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	return nil
+}
+
+func NotChecked() error {
+	err := errors.New("error")
+
+	return fmt.Errorf("open: %v", err)
+}
+
+func FormattedWithVNoLint() error {
+	err := errors.New("error")
+
+	if err != nil {
+		return fmt.Errorf("open: %v", err) //nolint:correcterr
+	}
+
+	return nil
+}